@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"strings"
@@ -248,6 +249,22 @@ func (h *HTTPClient) ListBookedAppointments(ctx context.Context, opts *ListBooke
 	}, nil
 }
 
+// AllBookedAppointments iterates over booked appointments, calling ListBookedAppointments for each
+// page as needed. Iteration starts at opts.Pagination.Offset and stops after
+// opts.Pagination.MaxItems appointments if set.
+func (h *HTTPClient) AllBookedAppointments(ctx context.Context, opts *ListBookedAppointmentsOptions) iter.Seq2[*BookedAppointment, error] {
+	pagination := func(o *ListBookedAppointmentsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListBookedAppointmentsOptions) ([]*BookedAppointment, *PaginationResult, error) {
+		res, err := h.ListBookedAppointments(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.BookedAppointments, res.Pagination, nil
+	})
+}
+
 type ListChangedAppointmentsOptions struct {
 	DepartmentID               string
 	LeaveUnprocessed           bool
@@ -499,6 +516,10 @@ type ListOpenAppointmentSlotOptions struct {
 
 	// Starting point of entries; 0-indexed
 	Offset int
+
+	// MaxItems caps the number of slots yielded by AllOpenAppointmentSlots. It is ignored by
+	// ListOpenAppointmentSlots.
+	MaxItems int
 }
 
 type OpenAppointmentSlot struct {
@@ -602,6 +623,35 @@ func (h *HTTPClient) ListOpenAppointmentSlots(ctx context.Context, departmentID
 	}, nil
 }
 
+// AllOpenAppointmentSlots iterates over open appointment slots, calling ListOpenAppointmentSlots
+// for each page as needed. Set opts.MaxItems to stop early.
+func (h *HTTPClient) AllOpenAppointmentSlots(ctx context.Context, departmentID int, opts *ListOpenAppointmentSlotOptions) iter.Seq2[*OpenAppointmentSlot, error] {
+	var pagination *PaginationOptions
+	if opts != nil {
+		pagination = &PaginationOptions{
+			Limit:    opts.Limit,
+			Offset:   opts.Offset,
+			MaxItems: opts.MaxItems,
+		}
+	}
+
+	return paginate(ctx, pagination, func(ctx context.Context, p *PaginationOptions) ([]*OpenAppointmentSlot, *PaginationResult, error) {
+		pageOpts := &ListOpenAppointmentSlotOptions{}
+		if opts != nil {
+			*pageOpts = *opts
+		}
+		pageOpts.Limit = p.Limit
+		pageOpts.Offset = p.Offset
+
+		res, err := h.ListOpenAppointmentSlots(ctx, departmentID, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Appointments, res.Pagination, nil
+	})
+}
+
 type BookAppointmentOptions struct {
 	AppointmentTypeID           int
 	BookingNote                 string
@@ -867,7 +917,7 @@ func (h *HTTPClient) freezeOrUnfreezeAppointmentSlot(ctx context.Context, appoin
 		}
 	}
 
-	_, err := h.PutForm(ctx, fmt.Sprintf("/appointments/%s/freeze", appointmentID), q, &out)
+	res, err := h.PutForm(ctx, fmt.Sprintf("/appointments/%s/freeze", appointmentID), q, &out)
 	if err != nil {
 		return err
 	}
@@ -881,7 +931,7 @@ func (h *HTTPClient) freezeOrUnfreezeAppointmentSlot(ctx context.Context, appoin
 			return ErrAppointmentSlotAlreadyUnfrozen
 		}
 
-		return errors.New(out.ErrorMessage)
+		return fmt.Errorf("freezing or unfreezing appointment slot: %w", envelopeError(res))
 	}
 
 	return nil
@@ -260,6 +260,33 @@ func TestHTTPClient_ListOpenAppointmentSlots(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestHTTPClient_AllOpenAppointmentSlots(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		assert.Equal("2", r.URL.Query().Get("limit"))
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		_, _ = fmt.Fprintf(w, `{"next":"/appointments/open?limit=2&offset=%d","appointments":[{"appointmentid":%d},{"appointmentid":%d}]}`, offset+2, offset+1, offset+2)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var appointmentIDs []int
+
+	for slot, err := range athenaClient.AllOpenAppointmentSlots(context.Background(), 1, &ListOpenAppointmentSlotOptions{Limit: 2, MaxItems: 3}) {
+		assert.NoError(err)
+		appointmentIDs = append(appointmentIDs, slot.AppointmentID)
+	}
+
+	assert.Equal([]int{1, 2, 3}, appointmentIDs)
+	assert.Equal(2, calls)
+}
+
 func TestHTTPClient_BookAppointment(t *testing.T) {
 	assert := assert.New(t)
 
@@ -504,3 +531,23 @@ func TestHTTPClient_FreezeAppointmentSlot(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPClient_FreezeAppointmentSlot_unsuccessful(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := os.ReadFile("./resources/FreezeAppointmentSlotError.json")
+		_, _ = w.Write(b)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	err := athenaClient.FreezeAppointmentSlot(context.Background(), "1230322", nil)
+	assert.ErrorIs(err, ErrValidation)
+
+	apiErr := &APIError{}
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal("oops", apiErr.AthenaError)
+	assert.NotEmpty(apiErr.XRequestID)
+}
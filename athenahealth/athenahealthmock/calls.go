@@ -0,0 +1,117 @@
+// Package athenahealthmock provides Client, a programmable mock of athenahealth.Client for tests.
+//
+//	client := &athenahealthmock.Client{
+//		GetPatientFunc: func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+//			return &athenahealth.Patient{PatientID: patientID}, nil
+//		},
+//	}
+//
+//	// ... exercise code that uses client ...
+//
+//	client.AssertCallCount(t, "GetPatient", 1)
+package athenahealthmock
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Call is a recorded call to a Client method.
+type Call struct {
+	Method string
+	// Args are the arguments the method was called with, including the context.
+	Args []any
+}
+
+// TestingT is the subset of testing.TB used by the assertion methods.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+func (c *Client) record(method string, args ...any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.calls = append(c.calls, &Call{
+		Method: method,
+		Args:   args,
+	})
+}
+
+func unimplemented(method string) string {
+	return fmt.Sprintf("athenahealthmock: %s called but %sFunc is nil", method, method)
+}
+
+// Calls returns every recorded call in the order the calls were made.
+func (c *Client) Calls() []*Call {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return slices.Clone(c.calls)
+}
+
+// CallsTo returns the recorded calls to method in the order they were made.
+func (c *Client) CallsTo(method string) []*Call {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var calls []*Call
+
+	for _, call := range c.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// CallCount returns the number of recorded calls to method.
+func (c *Client) CallCount(method string) int {
+	return len(c.CallsTo(method))
+}
+
+// Reset discards the recorded calls. Func fields are left as they are.
+func (c *Client) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.calls = nil
+}
+
+// AssertCalled fails the test if method was not called.
+func (c *Client) AssertCalled(t TestingT, method string) bool {
+	t.Helper()
+
+	if c.CallCount(method) == 0 {
+		t.Errorf("athenahealthmock: expected %s to be called", method)
+		return false
+	}
+
+	return true
+}
+
+// AssertNotCalled fails the test if method was called.
+func (c *Client) AssertNotCalled(t TestingT, method string) bool {
+	t.Helper()
+
+	if n := c.CallCount(method); n > 0 {
+		t.Errorf("athenahealthmock: expected %s not to be called, but it was called %d time(s)", method, n)
+		return false
+	}
+
+	return true
+}
+
+// AssertCallCount fails the test if method was not called exactly n times.
+func (c *Client) AssertCallCount(t TestingT, method string, n int) bool {
+	t.Helper()
+
+	if count := c.CallCount(method); count != n {
+		t.Errorf("athenahealthmock: expected %s to be called %d time(s), but it was called %d time(s)", method, n, count)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package athenahealthmock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+type testT struct {
+	errors []string
+}
+
+func (t *testT) Helper() {}
+
+func (t *testT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestClient_assertions(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{
+		ListCustomFieldsFunc: func(ctx context.Context) ([]*athenahealth.CustomField, error) {
+			return nil, nil
+		},
+	}
+
+	_, _ = client.ListCustomFields(context.Background())
+
+	tt := &testT{}
+
+	assert.False(client.AssertCalled(tt, "ListDepartments"))
+	assert.False(client.AssertNotCalled(tt, "ListCustomFields"))
+	assert.False(client.AssertCallCount(tt, "ListCustomFields", 2))
+	assert.Equal([]string{
+		"athenahealthmock: expected ListDepartments to be called",
+		"athenahealthmock: expected ListCustomFields not to be called, but it was called 1 time(s)",
+		"athenahealthmock: expected ListCustomFields to be called 2 time(s), but it was called 1 time(s)",
+	}, tt.errors)
+}
+
+func TestClient_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{
+		ListCustomFieldsFunc: func(ctx context.Context) ([]*athenahealth.CustomField, error) {
+			return nil, nil
+		},
+	}
+
+	_, _ = client.ListCustomFields(context.Background())
+	assert.Len(client.Calls(), 1)
+
+	client.Reset()
+	assert.Empty(client.Calls())
+	assert.NotNil(client.ListCustomFieldsFunc)
+}
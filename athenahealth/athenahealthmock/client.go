@@ -0,0 +1,1154 @@
+package athenahealthmock
+
+import (
+	"context"
+	"io"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// Client is a programmable mock of athenahealth.Client. Set the func field named after a method
+// to control what it returns; calling a method whose func field is nil panics. Every call is
+// recorded, including calls that panic.
+type Client struct {
+	// Department
+	DepartmentGetRequiredCheckInFieldsFunc func(ctx context.Context, deptID string) (*athenahealth.GetRequiredCheckInFieldsResult, error)
+	GetDepartmentFunc                      func(ctx context.Context, departmentID string) (*athenahealth.Department, error)
+	ListDepartmentsFunc                    func(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) (*athenahealth.ListDepartmentsResult, error)
+
+	// Patient
+	CreatePatientFunc                               func(ctx context.Context, opts *athenahealth.CreatePatientOptions) (string, error)
+	GetPatientFunc                                  func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error)
+	GetPatientsFunc                                 func(ctx context.Context, id string, opts *athenahealth.GetPatientOptions) ([]*athenahealth.Patient, error)
+	ListPatientsFunc                                func(ctx context.Context, opts *athenahealth.ListPatientsOptions) (*athenahealth.ListPatientsResult, error)
+	UpdatePatientFunc                               func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientOptions) (*athenahealth.UpdatePatientResult, error)
+	UpdatePatientInformationVerificationDetailsFunc func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientInformationVerificationDetailsOptions) error
+	UpdatePatientMedicationHistoryConsentFunc       func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientMedicationHistoryConsentOptions) error
+
+	// Patient Photo
+	GetPatientPhotoFunc          func(ctx context.Context, patientID string, opts *athenahealth.GetPatientPhotoOptions) (string, error)
+	UpdatePatientPhotoFunc       func(ctx context.Context, patientID string, data []byte) error
+	UpdatePatientPhotoReaderFunc func(ctx context.Context, patientID string, r io.Reader) error
+
+	// Patient Problems
+	ListProblemsFunc func(ctx context.Context, patientID string, opts *athenahealth.ListProblemsOptions) ([]*athenahealth.Problem, error)
+
+	// Patient Documents
+	ListAdminDocumentsFunc        func(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) (*athenahealth.ListAdminDocumentsResult, error)
+	AddDocumentFunc               func(ctx context.Context, patientID string, opts *athenahealth.AddDocumentOptions) (string, error)
+	AddDocumentReaderFunc         func(ctx context.Context, patientID string, opts *athenahealth.AddDocumentReaderOptions) (string, error)
+	AddClinicalDocumentFunc       func(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentOptions) (*athenahealth.AddClinicalDocumentResponse, error)
+	AddClinicalDocumentReaderFunc func(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentReaderOptions) (*athenahealth.AddClinicalDocumentResponse, error)
+	AddPatientCaseDocumentFunc    func(ctx context.Context, patientID string, opts *athenahealth.AddPatientCaseDocumentOptions) (int, error)
+	DeleteClinicalDocumentFunc    func(ctx context.Context, patientID string, clinicalDocumentID string) (*athenahealth.DeleteClinicalDocumentResponse, error)
+
+	// Patient Custom Fields
+	ListPatientsMatchingCustomFieldFunc func(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) (*athenahealth.ListPatientsMatchingCustomFieldResult, error)
+	ListCustomFieldsFunc                func(ctx context.Context) ([]*athenahealth.CustomField, error)
+	GetPatientCustomFieldsFunc          func(ctx context.Context, patientID string, departmentID string) ([]*athenahealth.CustomFieldValue, error)
+	UpdatePatientCustomFieldsFunc       func(ctx context.Context, patientID string, departmentID string, customFields []*athenahealth.CustomFieldValue) error
+
+	// Patient Insurance
+	CreatePatientInsurancePackageFunc         func(ctx context.Context, opts *athenahealth.CreatePatientInsurancePackageOptions) (*athenahealth.InsurancePackage, error)
+	DeletePatientInsurancePackageFunc         func(ctx context.Context, patientID string, insuranceID string, cancellationNote string) error
+	ListPatientInsurancePackagesFunc          func(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) (*athenahealth.ListPatientInsurancePackagesResult, error)
+	UpdatePatientInsurancePackageFunc         func(ctx context.Context, opts *athenahealth.UpdatePatientInsurancePackageOptions) error
+	ReactivatePatientInsurancePackageFunc     func(ctx context.Context, patientID string, insuranceID string, expirationDate *time.Time) error
+	UploadPatientInsuranceCardImageFunc       func(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error)
+	UploadPatientInsuranceCardImageReaderFunc func(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageReaderOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error)
+	GetPatientInsuranceCardImageFunc          func(ctx context.Context, patientID string, insuranceID string) (*athenahealth.GetPatientInsuranceCardImageResult, error)
+
+	// Patient Drivers License
+	AddPatientDriversLicenseDocumentFunc       func(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error)
+	AddPatientDriversLicenseDocumentReaderFunc func(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentReaderOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error)
+
+	// Patient Lab Results
+	AddLabResultDocumentFunc       func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentOptions) (int, error)
+	AddLabResultDocumentReaderFunc func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentReaderOptions) (int, error)
+	ListLabResultsFunc             func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) (*athenahealth.ListLabResultsResult, error)
+	ListChangedLabResultsFunc      func(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) (*athenahealth.ListChangedLabResultsResult, error)
+
+	// Health history
+	ListSocialHistoryTemplatesFunc            func(ctx context.Context) ([]*athenahealth.SocialHistoryTemplate, error)
+	GetPatientSocialHistoryFunc               func(ctx context.Context, patientID string, opts *athenahealth.GetPatientSocialHistoryOptions) (*athenahealth.GetPatientSocialHistoryResponse, error)
+	UpdatePatientSocialHistoryFunc            func(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientSocialHistoryOptions) error
+	GetHealthHistoryFormForAppointmentFunc    func(ctx context.Context, appointmentID string, formID string) (*athenahealth.HealthHistoryForm, error)
+	UpdateHealthHistoryFormForAppointmentFunc func(ctx context.Context, appointmentID string, formID string, form *athenahealth.HealthHistoryForm) error
+
+	// Medications and Allergies
+	SearchAllergiesFunc   func(ctx context.Context, searchVal string) ([]*athenahealth.Allergy, error)
+	ListMedicationsFunc   func(ctx context.Context, patientID string, opts *athenahealth.ListMedicationsOptions) (*athenahealth.ListMedicationsResult, error)
+	SearchMedicationsFunc func(ctx context.Context, searchVal string) ([]*athenahealth.SearchMedicationsResult, error)
+
+	// Appointment
+	GetAppointmentFunc              func(ctx context.Context, appointmentID string) (*athenahealth.Appointment, error)
+	ListBookedAppointmentsFunc      func(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) (*athenahealth.ListBookedAppointmentsResult, error)
+	ListChangedAppointmentsFunc     func(ctx context.Context, opts *athenahealth.ListChangedAppointmentsOptions) ([]*athenahealth.BookedAppointment, error)
+	ListOpenAppointmentSlotsFunc    func(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) (*athenahealth.ListOpenAppointmentSlotsResult, error)
+	BookAppointmentFunc             func(ctx context.Context, patientID string, apptID string, opts *athenahealth.BookAppointmentOptions) (*athenahealth.BookedAppointment, error)
+	UpdateBookedAppointmentFunc     func(ctx context.Context, apptID string, opts *athenahealth.UpdateBookedAppointmentOptions) error
+	RescheduleAppointmentFunc       func(ctx context.Context, apptID int, opts *athenahealth.RescheduleAppointmentOptions) (*athenahealth.RescheduleAppointmentResult, error)
+	ListAppointmentRemindersFunc    func(ctx context.Context, opts *athenahealth.ListAppointmentRemindersOptions) (*athenahealth.ListAppointmentRemindersResult, error)
+	CreateAppointmentSlotFunc       func(ctx context.Context, opts *athenahealth.CreateAppointmentSlotOptions) (*athenahealth.CreateAppointmentSlotResult, error)
+	CreateAppointmentTypeFunc       func(ctx context.Context, options *athenahealth.CreateAppointmentTypeOptions) (*athenahealth.CreateAppointmentTypeResult, error)
+	ListAppointmentCustomFieldsFunc func(ctx context.Context) ([]*athenahealth.AppointmentCustomField, error)
+	FreezeAppointmentSlotFunc       func(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error
+	UnfreezeAppointmentSlotFunc     func(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error
+
+	// Appointment Check-In
+	AppointmentCancelCheckInFunc func(ctx context.Context, apptID string) error
+	AppointmentCheckInFunc       func(ctx context.Context, apptID string) error
+	AppointmentCheckOutFunc      func(ctx context.Context, apptID string) error
+	AppointmentStartCheckInFunc  func(ctx context.Context, apptID string) error
+
+	// Appointment Note
+	CreateAppointmentNoteFunc func(ctx context.Context, appointmentID string, opts *athenahealth.CreateAppointmentNoteOptions) error
+	DeleteAppointmentNoteFunc func(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.DeleteAppointmentNoteOptions) error
+	ListAppointmentNotesFunc  func(ctx context.Context, appointmentID string, opts *athenahealth.ListAppointmentNotesOptions) ([]*athenahealth.AppointmentNote, error)
+	UpdateAppointmentNoteFunc func(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.UpdateAppointmentNoteOptions) error
+
+	// Encounter
+	GetPhysicalExamFunc        func(ctx context.Context, encounterID string, opts *athenahealth.GetPhysicalExamOpts) (*athenahealth.PhysicalExam, error)
+	ListEncounterDocumentsFunc func(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) (*athenahealth.ListEncounterDocumentsResult, error)
+	EncounterSummaryFunc       func(ctx context.Context, encounterID string, opts *athenahealth.EncounterSummaryOptions) (*athenahealth.EncounterSummaryResponse, error)
+
+	// Provider
+	ListProvidersFunc func(ctx context.Context, opts *athenahealth.ListProvidersOptions) (*athenahealth.ListProvidersResult, error)
+	GetProviderFunc   func(ctx context.Context, providerID string) (*athenahealth.Provider, error)
+
+	// Subscription
+	GetSubscriptionFunc        func(ctx context.Context, feedType athenahealth.FeedType) (*athenahealth.Subscription, error)
+	ListSubscriptionEventsFunc func(ctx context.Context, feedType athenahealth.FeedType) ([]*athenahealth.SubscriptionEvent, error)
+	SubscribeFunc              func(ctx context.Context, feedType athenahealth.FeedType, opts *athenahealth.SubscribeOptions) error
+	UnsubscribeFunc            func(ctx context.Context, feedType athenahealth.FeedType, opts *athenahealth.UnsubscribeOptions) error
+	EnsureSubscriptionsFunc    func(ctx context.Context, desired map[athenahealth.FeedType][]string) (*athenahealth.SubscriptionReport, error)
+
+	// List Changed
+	ListChangedPatientsFunc      func(ctx context.Context, opts *athenahealth.ListChangedPatientOptions) ([]*athenahealth.Patient, error)
+	ListChangedProvidersFunc     func(ctx context.Context, opts *athenahealth.ListChangedProviderOptions) ([]*athenahealth.Provider, error)
+	ListChangedProblemsFunc      func(ctx context.Context, opts *athenahealth.ListChangedProblemsOptions) ([]*athenahealth.ChangedProblem, error)
+	ListChangedPrescriptionsFunc func(ctx context.Context, options *athenahealth.ListChangedPrescriptionsOptions) (*athenahealth.ListChangedPrescriptionsResult, error)
+
+	// Prescriptions
+	UpdatePrescriptionFunc func(ctx context.Context, departmentID int, patientID int, documentID int, opts *athenahealth.UpdatePrescriptionOptions) (*athenahealth.UpdatePrescriptionResult, error)
+
+	// Claims
+	CreateFinancialClaimFunc func(ctx context.Context, opts *athenahealth.CreateClaimOptions) ([]string, error)
+	ListClaimsFunc           func(ctx context.Context, opts *athenahealth.ListClaimsOptions) (*athenahealth.ListClaimsResult, error)
+
+	// Telehealth
+	GetTelehealthInviteURLFunc func(ctx context.Context, apptID string) (*athenahealth.GetTelehealthInviteURLResult, error)
+
+	// Pagination iterators
+	AllBookedAppointmentsFunc          func(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) iter.Seq2[*athenahealth.BookedAppointment, error]
+	AllOpenAppointmentSlotsFunc        func(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) iter.Seq2[*athenahealth.OpenAppointmentSlot, error]
+	AllClaimsFunc                      func(ctx context.Context, opts *athenahealth.ListClaimsOptions) iter.Seq2[*athenahealth.Claim, error]
+	AllDepartmentsFunc                 func(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) iter.Seq2[*athenahealth.Department, error]
+	AllAdminDocumentsFunc              func(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) iter.Seq2[*athenahealth.AdminDocument, error]
+	AllEncounterDocumentsFunc          func(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) iter.Seq2[*athenahealth.EncounterDocument, error]
+	AllPatientInsurancePackagesFunc    func(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) iter.Seq2[*athenahealth.InsurancePackage, error]
+	AllLabResultsFunc                  func(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) iter.Seq2[*athenahealth.LabResult, error]
+	AllChangedLabResultsFunc           func(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) iter.Seq2[*athenahealth.ChangedLabResult, error]
+	AllPatientsFunc                    func(ctx context.Context, opts *athenahealth.ListPatientsOptions) iter.Seq2[*athenahealth.Patient, error]
+	AllPatientsMatchingCustomFieldFunc func(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) iter.Seq2[*athenahealth.Patient, error]
+	AllChangedPrescriptionsFunc        func(ctx context.Context, opts *athenahealth.ListChangedPrescriptionsOptions) iter.Seq2[*athenahealth.ChangedPrescription, error]
+	AllProvidersFunc                   func(ctx context.Context, opts *athenahealth.ListProvidersOptions) iter.Seq2[*athenahealth.Provider, error]
+
+	calls []*Call
+	lock  sync.Mutex
+}
+
+var _ athenahealth.Client = (*Client)(nil)
+
+func (c *Client) DepartmentGetRequiredCheckInFields(ctx context.Context, deptID string) (*athenahealth.GetRequiredCheckInFieldsResult, error) {
+	c.record("DepartmentGetRequiredCheckInFields", ctx, deptID)
+
+	if c.DepartmentGetRequiredCheckInFieldsFunc == nil {
+		panic(unimplemented("DepartmentGetRequiredCheckInFields"))
+	}
+
+	return c.DepartmentGetRequiredCheckInFieldsFunc(ctx, deptID)
+}
+
+func (c *Client) GetDepartment(ctx context.Context, departmentID string) (*athenahealth.Department, error) {
+	c.record("GetDepartment", ctx, departmentID)
+
+	if c.GetDepartmentFunc == nil {
+		panic(unimplemented("GetDepartment"))
+	}
+
+	return c.GetDepartmentFunc(ctx, departmentID)
+}
+
+func (c *Client) ListDepartments(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) (*athenahealth.ListDepartmentsResult, error) {
+	c.record("ListDepartments", ctx, opts)
+
+	if c.ListDepartmentsFunc == nil {
+		panic(unimplemented("ListDepartments"))
+	}
+
+	return c.ListDepartmentsFunc(ctx, opts)
+}
+
+func (c *Client) CreatePatient(ctx context.Context, opts *athenahealth.CreatePatientOptions) (string, error) {
+	c.record("CreatePatient", ctx, opts)
+
+	if c.CreatePatientFunc == nil {
+		panic(unimplemented("CreatePatient"))
+	}
+
+	return c.CreatePatientFunc(ctx, opts)
+}
+
+func (c *Client) GetPatient(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+	c.record("GetPatient", ctx, patientID, opts)
+
+	if c.GetPatientFunc == nil {
+		panic(unimplemented("GetPatient"))
+	}
+
+	return c.GetPatientFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetPatients(ctx context.Context, id string, opts *athenahealth.GetPatientOptions) ([]*athenahealth.Patient, error) {
+	c.record("GetPatients", ctx, id, opts)
+
+	if c.GetPatientsFunc == nil {
+		panic(unimplemented("GetPatients"))
+	}
+
+	return c.GetPatientsFunc(ctx, id, opts)
+}
+
+func (c *Client) ListPatients(ctx context.Context, opts *athenahealth.ListPatientsOptions) (*athenahealth.ListPatientsResult, error) {
+	c.record("ListPatients", ctx, opts)
+
+	if c.ListPatientsFunc == nil {
+		panic(unimplemented("ListPatients"))
+	}
+
+	return c.ListPatientsFunc(ctx, opts)
+}
+
+func (c *Client) UpdatePatient(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientOptions) (*athenahealth.UpdatePatientResult, error) {
+	c.record("UpdatePatient", ctx, patientID, opts)
+
+	if c.UpdatePatientFunc == nil {
+		panic(unimplemented("UpdatePatient"))
+	}
+
+	return c.UpdatePatientFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientInformationVerificationDetails(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientInformationVerificationDetailsOptions) error {
+	c.record("UpdatePatientInformationVerificationDetails", ctx, patientID, opts)
+
+	if c.UpdatePatientInformationVerificationDetailsFunc == nil {
+		panic(unimplemented("UpdatePatientInformationVerificationDetails"))
+	}
+
+	return c.UpdatePatientInformationVerificationDetailsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientMedicationHistoryConsent(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientMedicationHistoryConsentOptions) error {
+	c.record("UpdatePatientMedicationHistoryConsent", ctx, patientID, opts)
+
+	if c.UpdatePatientMedicationHistoryConsentFunc == nil {
+		panic(unimplemented("UpdatePatientMedicationHistoryConsent"))
+	}
+
+	return c.UpdatePatientMedicationHistoryConsentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetPatientPhoto(ctx context.Context, patientID string, opts *athenahealth.GetPatientPhotoOptions) (string, error) {
+	c.record("GetPatientPhoto", ctx, patientID, opts)
+
+	if c.GetPatientPhotoFunc == nil {
+		panic(unimplemented("GetPatientPhoto"))
+	}
+
+	return c.GetPatientPhotoFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientPhoto(ctx context.Context, patientID string, data []byte) error {
+	c.record("UpdatePatientPhoto", ctx, patientID, data)
+
+	if c.UpdatePatientPhotoFunc == nil {
+		panic(unimplemented("UpdatePatientPhoto"))
+	}
+
+	return c.UpdatePatientPhotoFunc(ctx, patientID, data)
+}
+
+func (c *Client) UpdatePatientPhotoReader(ctx context.Context, patientID string, r io.Reader) error {
+	c.record("UpdatePatientPhotoReader", ctx, patientID, r)
+
+	if c.UpdatePatientPhotoReaderFunc == nil {
+		panic(unimplemented("UpdatePatientPhotoReader"))
+	}
+
+	return c.UpdatePatientPhotoReaderFunc(ctx, patientID, r)
+}
+
+func (c *Client) ListProblems(ctx context.Context, patientID string, opts *athenahealth.ListProblemsOptions) ([]*athenahealth.Problem, error) {
+	c.record("ListProblems", ctx, patientID, opts)
+
+	if c.ListProblemsFunc == nil {
+		panic(unimplemented("ListProblems"))
+	}
+
+	return c.ListProblemsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) ListAdminDocuments(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) (*athenahealth.ListAdminDocumentsResult, error) {
+	c.record("ListAdminDocuments", ctx, patientID, opts)
+
+	if c.ListAdminDocumentsFunc == nil {
+		panic(unimplemented("ListAdminDocuments"))
+	}
+
+	return c.ListAdminDocumentsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddDocument(ctx context.Context, patientID string, opts *athenahealth.AddDocumentOptions) (string, error) {
+	c.record("AddDocument", ctx, patientID, opts)
+
+	if c.AddDocumentFunc == nil {
+		panic(unimplemented("AddDocument"))
+	}
+
+	return c.AddDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddDocumentReaderOptions) (string, error) {
+	c.record("AddDocumentReader", ctx, patientID, opts)
+
+	if c.AddDocumentReaderFunc == nil {
+		panic(unimplemented("AddDocumentReader"))
+	}
+
+	return c.AddDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddClinicalDocument(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentOptions) (*athenahealth.AddClinicalDocumentResponse, error) {
+	c.record("AddClinicalDocument", ctx, patientID, opts)
+
+	if c.AddClinicalDocumentFunc == nil {
+		panic(unimplemented("AddClinicalDocument"))
+	}
+
+	return c.AddClinicalDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddClinicalDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddClinicalDocumentReaderOptions) (*athenahealth.AddClinicalDocumentResponse, error) {
+	c.record("AddClinicalDocumentReader", ctx, patientID, opts)
+
+	if c.AddClinicalDocumentReaderFunc == nil {
+		panic(unimplemented("AddClinicalDocumentReader"))
+	}
+
+	return c.AddClinicalDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddPatientCaseDocument(ctx context.Context, patientID string, opts *athenahealth.AddPatientCaseDocumentOptions) (int, error) {
+	c.record("AddPatientCaseDocument", ctx, patientID, opts)
+
+	if c.AddPatientCaseDocumentFunc == nil {
+		panic(unimplemented("AddPatientCaseDocument"))
+	}
+
+	return c.AddPatientCaseDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) DeleteClinicalDocument(ctx context.Context, patientID string, clinicalDocumentID string) (*athenahealth.DeleteClinicalDocumentResponse, error) {
+	c.record("DeleteClinicalDocument", ctx, patientID, clinicalDocumentID)
+
+	if c.DeleteClinicalDocumentFunc == nil {
+		panic(unimplemented("DeleteClinicalDocument"))
+	}
+
+	return c.DeleteClinicalDocumentFunc(ctx, patientID, clinicalDocumentID)
+}
+
+func (c *Client) ListPatientsMatchingCustomField(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) (*athenahealth.ListPatientsMatchingCustomFieldResult, error) {
+	c.record("ListPatientsMatchingCustomField", ctx, opts)
+
+	if c.ListPatientsMatchingCustomFieldFunc == nil {
+		panic(unimplemented("ListPatientsMatchingCustomField"))
+	}
+
+	return c.ListPatientsMatchingCustomFieldFunc(ctx, opts)
+}
+
+func (c *Client) ListCustomFields(ctx context.Context) ([]*athenahealth.CustomField, error) {
+	c.record("ListCustomFields", ctx)
+
+	if c.ListCustomFieldsFunc == nil {
+		panic(unimplemented("ListCustomFields"))
+	}
+
+	return c.ListCustomFieldsFunc(ctx)
+}
+
+func (c *Client) GetPatientCustomFields(ctx context.Context, patientID string, departmentID string) ([]*athenahealth.CustomFieldValue, error) {
+	c.record("GetPatientCustomFields", ctx, patientID, departmentID)
+
+	if c.GetPatientCustomFieldsFunc == nil {
+		panic(unimplemented("GetPatientCustomFields"))
+	}
+
+	return c.GetPatientCustomFieldsFunc(ctx, patientID, departmentID)
+}
+
+func (c *Client) UpdatePatientCustomFields(ctx context.Context, patientID string, departmentID string, customFields []*athenahealth.CustomFieldValue) error {
+	c.record("UpdatePatientCustomFields", ctx, patientID, departmentID, customFields)
+
+	if c.UpdatePatientCustomFieldsFunc == nil {
+		panic(unimplemented("UpdatePatientCustomFields"))
+	}
+
+	return c.UpdatePatientCustomFieldsFunc(ctx, patientID, departmentID, customFields)
+}
+
+func (c *Client) CreatePatientInsurancePackage(ctx context.Context, opts *athenahealth.CreatePatientInsurancePackageOptions) (*athenahealth.InsurancePackage, error) {
+	c.record("CreatePatientInsurancePackage", ctx, opts)
+
+	if c.CreatePatientInsurancePackageFunc == nil {
+		panic(unimplemented("CreatePatientInsurancePackage"))
+	}
+
+	return c.CreatePatientInsurancePackageFunc(ctx, opts)
+}
+
+func (c *Client) DeletePatientInsurancePackage(ctx context.Context, patientID string, insuranceID string, cancellationNote string) error {
+	c.record("DeletePatientInsurancePackage", ctx, patientID, insuranceID, cancellationNote)
+
+	if c.DeletePatientInsurancePackageFunc == nil {
+		panic(unimplemented("DeletePatientInsurancePackage"))
+	}
+
+	return c.DeletePatientInsurancePackageFunc(ctx, patientID, insuranceID, cancellationNote)
+}
+
+func (c *Client) ListPatientInsurancePackages(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) (*athenahealth.ListPatientInsurancePackagesResult, error) {
+	c.record("ListPatientInsurancePackages", ctx, opts)
+
+	if c.ListPatientInsurancePackagesFunc == nil {
+		panic(unimplemented("ListPatientInsurancePackages"))
+	}
+
+	return c.ListPatientInsurancePackagesFunc(ctx, opts)
+}
+
+func (c *Client) UpdatePatientInsurancePackage(ctx context.Context, opts *athenahealth.UpdatePatientInsurancePackageOptions) error {
+	c.record("UpdatePatientInsurancePackage", ctx, opts)
+
+	if c.UpdatePatientInsurancePackageFunc == nil {
+		panic(unimplemented("UpdatePatientInsurancePackage"))
+	}
+
+	return c.UpdatePatientInsurancePackageFunc(ctx, opts)
+}
+
+func (c *Client) ReactivatePatientInsurancePackage(ctx context.Context, patientID string, insuranceID string, expirationDate *time.Time) error {
+	c.record("ReactivatePatientInsurancePackage", ctx, patientID, insuranceID, expirationDate)
+
+	if c.ReactivatePatientInsurancePackageFunc == nil {
+		panic(unimplemented("ReactivatePatientInsurancePackage"))
+	}
+
+	return c.ReactivatePatientInsurancePackageFunc(ctx, patientID, insuranceID, expirationDate)
+}
+
+func (c *Client) UploadPatientInsuranceCardImage(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error) {
+	c.record("UploadPatientInsuranceCardImage", ctx, patientID, insuranceID, opts)
+
+	if c.UploadPatientInsuranceCardImageFunc == nil {
+		panic(unimplemented("UploadPatientInsuranceCardImage"))
+	}
+
+	return c.UploadPatientInsuranceCardImageFunc(ctx, patientID, insuranceID, opts)
+}
+
+func (c *Client) UploadPatientInsuranceCardImageReader(ctx context.Context, patientID string, insuranceID string, opts *athenahealth.UploadPatientInsuranceCardImageReaderOptions) (*athenahealth.UploadPatientInsuranceCardImageResult, error) {
+	c.record("UploadPatientInsuranceCardImageReader", ctx, patientID, insuranceID, opts)
+
+	if c.UploadPatientInsuranceCardImageReaderFunc == nil {
+		panic(unimplemented("UploadPatientInsuranceCardImageReader"))
+	}
+
+	return c.UploadPatientInsuranceCardImageReaderFunc(ctx, patientID, insuranceID, opts)
+}
+
+func (c *Client) GetPatientInsuranceCardImage(ctx context.Context, patientID string, insuranceID string) (*athenahealth.GetPatientInsuranceCardImageResult, error) {
+	c.record("GetPatientInsuranceCardImage", ctx, patientID, insuranceID)
+
+	if c.GetPatientInsuranceCardImageFunc == nil {
+		panic(unimplemented("GetPatientInsuranceCardImage"))
+	}
+
+	return c.GetPatientInsuranceCardImageFunc(ctx, patientID, insuranceID)
+}
+
+func (c *Client) AddPatientDriversLicenseDocument(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error) {
+	c.record("AddPatientDriversLicenseDocument", ctx, patientID, opts)
+
+	if c.AddPatientDriversLicenseDocumentFunc == nil {
+		panic(unimplemented("AddPatientDriversLicenseDocument"))
+	}
+
+	return c.AddPatientDriversLicenseDocumentFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddPatientDriversLicenseDocumentReader(ctx context.Context, patientID string, opts *athenahealth.AddPatientDriversLicenseDocumentReaderOptions) (*athenahealth.AddPatientDriversLicenseDocumentResult, error) {
+	c.record("AddPatientDriversLicenseDocumentReader", ctx, patientID, opts)
+
+	if c.AddPatientDriversLicenseDocumentReaderFunc == nil {
+		panic(unimplemented("AddPatientDriversLicenseDocumentReader"))
+	}
+
+	return c.AddPatientDriversLicenseDocumentReaderFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AddLabResultDocument(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentOptions) (int, error) {
+	c.record("AddLabResultDocument", ctx, patientID, departmentID, opts)
+
+	if c.AddLabResultDocumentFunc == nil {
+		panic(unimplemented("AddLabResultDocument"))
+	}
+
+	return c.AddLabResultDocumentFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) AddLabResultDocumentReader(ctx context.Context, patientID string, departmentID string, opts *athenahealth.AddLabResultDocumentReaderOptions) (int, error) {
+	c.record("AddLabResultDocumentReader", ctx, patientID, departmentID, opts)
+
+	if c.AddLabResultDocumentReaderFunc == nil {
+		panic(unimplemented("AddLabResultDocumentReader"))
+	}
+
+	return c.AddLabResultDocumentReaderFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) ListLabResults(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) (*athenahealth.ListLabResultsResult, error) {
+	c.record("ListLabResults", ctx, patientID, departmentID, opts)
+
+	if c.ListLabResultsFunc == nil {
+		panic(unimplemented("ListLabResults"))
+	}
+
+	return c.ListLabResultsFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) ListChangedLabResults(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) (*athenahealth.ListChangedLabResultsResult, error) {
+	c.record("ListChangedLabResults", ctx, opts)
+
+	if c.ListChangedLabResultsFunc == nil {
+		panic(unimplemented("ListChangedLabResults"))
+	}
+
+	return c.ListChangedLabResultsFunc(ctx, opts)
+}
+
+func (c *Client) ListSocialHistoryTemplates(ctx context.Context) ([]*athenahealth.SocialHistoryTemplate, error) {
+	c.record("ListSocialHistoryTemplates", ctx)
+
+	if c.ListSocialHistoryTemplatesFunc == nil {
+		panic(unimplemented("ListSocialHistoryTemplates"))
+	}
+
+	return c.ListSocialHistoryTemplatesFunc(ctx)
+}
+
+func (c *Client) GetPatientSocialHistory(ctx context.Context, patientID string, opts *athenahealth.GetPatientSocialHistoryOptions) (*athenahealth.GetPatientSocialHistoryResponse, error) {
+	c.record("GetPatientSocialHistory", ctx, patientID, opts)
+
+	if c.GetPatientSocialHistoryFunc == nil {
+		panic(unimplemented("GetPatientSocialHistory"))
+	}
+
+	return c.GetPatientSocialHistoryFunc(ctx, patientID, opts)
+}
+
+func (c *Client) UpdatePatientSocialHistory(ctx context.Context, patientID string, opts *athenahealth.UpdatePatientSocialHistoryOptions) error {
+	c.record("UpdatePatientSocialHistory", ctx, patientID, opts)
+
+	if c.UpdatePatientSocialHistoryFunc == nil {
+		panic(unimplemented("UpdatePatientSocialHistory"))
+	}
+
+	return c.UpdatePatientSocialHistoryFunc(ctx, patientID, opts)
+}
+
+func (c *Client) GetHealthHistoryFormForAppointment(ctx context.Context, appointmentID string, formID string) (*athenahealth.HealthHistoryForm, error) {
+	c.record("GetHealthHistoryFormForAppointment", ctx, appointmentID, formID)
+
+	if c.GetHealthHistoryFormForAppointmentFunc == nil {
+		panic(unimplemented("GetHealthHistoryFormForAppointment"))
+	}
+
+	return c.GetHealthHistoryFormForAppointmentFunc(ctx, appointmentID, formID)
+}
+
+func (c *Client) UpdateHealthHistoryFormForAppointment(ctx context.Context, appointmentID string, formID string, form *athenahealth.HealthHistoryForm) error {
+	c.record("UpdateHealthHistoryFormForAppointment", ctx, appointmentID, formID, form)
+
+	if c.UpdateHealthHistoryFormForAppointmentFunc == nil {
+		panic(unimplemented("UpdateHealthHistoryFormForAppointment"))
+	}
+
+	return c.UpdateHealthHistoryFormForAppointmentFunc(ctx, appointmentID, formID, form)
+}
+
+func (c *Client) SearchAllergies(ctx context.Context, searchVal string) ([]*athenahealth.Allergy, error) {
+	c.record("SearchAllergies", ctx, searchVal)
+
+	if c.SearchAllergiesFunc == nil {
+		panic(unimplemented("SearchAllergies"))
+	}
+
+	return c.SearchAllergiesFunc(ctx, searchVal)
+}
+
+func (c *Client) ListMedications(ctx context.Context, patientID string, opts *athenahealth.ListMedicationsOptions) (*athenahealth.ListMedicationsResult, error) {
+	c.record("ListMedications", ctx, patientID, opts)
+
+	if c.ListMedicationsFunc == nil {
+		panic(unimplemented("ListMedications"))
+	}
+
+	return c.ListMedicationsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) SearchMedications(ctx context.Context, searchVal string) ([]*athenahealth.SearchMedicationsResult, error) {
+	c.record("SearchMedications", ctx, searchVal)
+
+	if c.SearchMedicationsFunc == nil {
+		panic(unimplemented("SearchMedications"))
+	}
+
+	return c.SearchMedicationsFunc(ctx, searchVal)
+}
+
+func (c *Client) GetAppointment(ctx context.Context, appointmentID string) (*athenahealth.Appointment, error) {
+	c.record("GetAppointment", ctx, appointmentID)
+
+	if c.GetAppointmentFunc == nil {
+		panic(unimplemented("GetAppointment"))
+	}
+
+	return c.GetAppointmentFunc(ctx, appointmentID)
+}
+
+func (c *Client) ListBookedAppointments(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) (*athenahealth.ListBookedAppointmentsResult, error) {
+	c.record("ListBookedAppointments", ctx, opts)
+
+	if c.ListBookedAppointmentsFunc == nil {
+		panic(unimplemented("ListBookedAppointments"))
+	}
+
+	return c.ListBookedAppointmentsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedAppointments(ctx context.Context, opts *athenahealth.ListChangedAppointmentsOptions) ([]*athenahealth.BookedAppointment, error) {
+	c.record("ListChangedAppointments", ctx, opts)
+
+	if c.ListChangedAppointmentsFunc == nil {
+		panic(unimplemented("ListChangedAppointments"))
+	}
+
+	return c.ListChangedAppointmentsFunc(ctx, opts)
+}
+
+func (c *Client) ListOpenAppointmentSlots(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) (*athenahealth.ListOpenAppointmentSlotsResult, error) {
+	c.record("ListOpenAppointmentSlots", ctx, departmentID, opts)
+
+	if c.ListOpenAppointmentSlotsFunc == nil {
+		panic(unimplemented("ListOpenAppointmentSlots"))
+	}
+
+	return c.ListOpenAppointmentSlotsFunc(ctx, departmentID, opts)
+}
+
+func (c *Client) BookAppointment(ctx context.Context, patientID string, apptID string, opts *athenahealth.BookAppointmentOptions) (*athenahealth.BookedAppointment, error) {
+	c.record("BookAppointment", ctx, patientID, apptID, opts)
+
+	if c.BookAppointmentFunc == nil {
+		panic(unimplemented("BookAppointment"))
+	}
+
+	return c.BookAppointmentFunc(ctx, patientID, apptID, opts)
+}
+
+func (c *Client) UpdateBookedAppointment(ctx context.Context, apptID string, opts *athenahealth.UpdateBookedAppointmentOptions) error {
+	c.record("UpdateBookedAppointment", ctx, apptID, opts)
+
+	if c.UpdateBookedAppointmentFunc == nil {
+		panic(unimplemented("UpdateBookedAppointment"))
+	}
+
+	return c.UpdateBookedAppointmentFunc(ctx, apptID, opts)
+}
+
+func (c *Client) RescheduleAppointment(ctx context.Context, apptID int, opts *athenahealth.RescheduleAppointmentOptions) (*athenahealth.RescheduleAppointmentResult, error) {
+	c.record("RescheduleAppointment", ctx, apptID, opts)
+
+	if c.RescheduleAppointmentFunc == nil {
+		panic(unimplemented("RescheduleAppointment"))
+	}
+
+	return c.RescheduleAppointmentFunc(ctx, apptID, opts)
+}
+
+func (c *Client) ListAppointmentReminders(ctx context.Context, opts *athenahealth.ListAppointmentRemindersOptions) (*athenahealth.ListAppointmentRemindersResult, error) {
+	c.record("ListAppointmentReminders", ctx, opts)
+
+	if c.ListAppointmentRemindersFunc == nil {
+		panic(unimplemented("ListAppointmentReminders"))
+	}
+
+	return c.ListAppointmentRemindersFunc(ctx, opts)
+}
+
+func (c *Client) CreateAppointmentSlot(ctx context.Context, opts *athenahealth.CreateAppointmentSlotOptions) (*athenahealth.CreateAppointmentSlotResult, error) {
+	c.record("CreateAppointmentSlot", ctx, opts)
+
+	if c.CreateAppointmentSlotFunc == nil {
+		panic(unimplemented("CreateAppointmentSlot"))
+	}
+
+	return c.CreateAppointmentSlotFunc(ctx, opts)
+}
+
+func (c *Client) CreateAppointmentType(ctx context.Context, options *athenahealth.CreateAppointmentTypeOptions) (*athenahealth.CreateAppointmentTypeResult, error) {
+	c.record("CreateAppointmentType", ctx, options)
+
+	if c.CreateAppointmentTypeFunc == nil {
+		panic(unimplemented("CreateAppointmentType"))
+	}
+
+	return c.CreateAppointmentTypeFunc(ctx, options)
+}
+
+func (c *Client) ListAppointmentCustomFields(ctx context.Context) ([]*athenahealth.AppointmentCustomField, error) {
+	c.record("ListAppointmentCustomFields", ctx)
+
+	if c.ListAppointmentCustomFieldsFunc == nil {
+		panic(unimplemented("ListAppointmentCustomFields"))
+	}
+
+	return c.ListAppointmentCustomFieldsFunc(ctx)
+}
+
+func (c *Client) FreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error {
+	c.record("FreezeAppointmentSlot", ctx, appointmentID, opts)
+
+	if c.FreezeAppointmentSlotFunc == nil {
+		panic(unimplemented("FreezeAppointmentSlot"))
+	}
+
+	return c.FreezeAppointmentSlotFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) UnfreezeAppointmentSlot(ctx context.Context, appointmentID string, opts *athenahealth.FreezeOrUnfreezeAppointmentSlotOptions) error {
+	c.record("UnfreezeAppointmentSlot", ctx, appointmentID, opts)
+
+	if c.UnfreezeAppointmentSlotFunc == nil {
+		panic(unimplemented("UnfreezeAppointmentSlot"))
+	}
+
+	return c.UnfreezeAppointmentSlotFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) AppointmentCancelCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentCancelCheckIn", ctx, apptID)
+
+	if c.AppointmentCancelCheckInFunc == nil {
+		panic(unimplemented("AppointmentCancelCheckIn"))
+	}
+
+	return c.AppointmentCancelCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentCheckIn", ctx, apptID)
+
+	if c.AppointmentCheckInFunc == nil {
+		panic(unimplemented("AppointmentCheckIn"))
+	}
+
+	return c.AppointmentCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentCheckOut(ctx context.Context, apptID string) error {
+	c.record("AppointmentCheckOut", ctx, apptID)
+
+	if c.AppointmentCheckOutFunc == nil {
+		panic(unimplemented("AppointmentCheckOut"))
+	}
+
+	return c.AppointmentCheckOutFunc(ctx, apptID)
+}
+
+func (c *Client) AppointmentStartCheckIn(ctx context.Context, apptID string) error {
+	c.record("AppointmentStartCheckIn", ctx, apptID)
+
+	if c.AppointmentStartCheckInFunc == nil {
+		panic(unimplemented("AppointmentStartCheckIn"))
+	}
+
+	return c.AppointmentStartCheckInFunc(ctx, apptID)
+}
+
+func (c *Client) CreateAppointmentNote(ctx context.Context, appointmentID string, opts *athenahealth.CreateAppointmentNoteOptions) error {
+	c.record("CreateAppointmentNote", ctx, appointmentID, opts)
+
+	if c.CreateAppointmentNoteFunc == nil {
+		panic(unimplemented("CreateAppointmentNote"))
+	}
+
+	return c.CreateAppointmentNoteFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) DeleteAppointmentNote(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.DeleteAppointmentNoteOptions) error {
+	c.record("DeleteAppointmentNote", ctx, appointmentID, noteID, opts)
+
+	if c.DeleteAppointmentNoteFunc == nil {
+		panic(unimplemented("DeleteAppointmentNote"))
+	}
+
+	return c.DeleteAppointmentNoteFunc(ctx, appointmentID, noteID, opts)
+}
+
+func (c *Client) ListAppointmentNotes(ctx context.Context, appointmentID string, opts *athenahealth.ListAppointmentNotesOptions) ([]*athenahealth.AppointmentNote, error) {
+	c.record("ListAppointmentNotes", ctx, appointmentID, opts)
+
+	if c.ListAppointmentNotesFunc == nil {
+		panic(unimplemented("ListAppointmentNotes"))
+	}
+
+	return c.ListAppointmentNotesFunc(ctx, appointmentID, opts)
+}
+
+func (c *Client) UpdateAppointmentNote(ctx context.Context, appointmentID string, noteID string, opts *athenahealth.UpdateAppointmentNoteOptions) error {
+	c.record("UpdateAppointmentNote", ctx, appointmentID, noteID, opts)
+
+	if c.UpdateAppointmentNoteFunc == nil {
+		panic(unimplemented("UpdateAppointmentNote"))
+	}
+
+	return c.UpdateAppointmentNoteFunc(ctx, appointmentID, noteID, opts)
+}
+
+func (c *Client) GetPhysicalExam(ctx context.Context, encounterID string, opts *athenahealth.GetPhysicalExamOpts) (*athenahealth.PhysicalExam, error) {
+	c.record("GetPhysicalExam", ctx, encounterID, opts)
+
+	if c.GetPhysicalExamFunc == nil {
+		panic(unimplemented("GetPhysicalExam"))
+	}
+
+	return c.GetPhysicalExamFunc(ctx, encounterID, opts)
+}
+
+func (c *Client) ListEncounterDocuments(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) (*athenahealth.ListEncounterDocumentsResult, error) {
+	c.record("ListEncounterDocuments", ctx, departmentID, patientID, opts)
+
+	if c.ListEncounterDocumentsFunc == nil {
+		panic(unimplemented("ListEncounterDocuments"))
+	}
+
+	return c.ListEncounterDocumentsFunc(ctx, departmentID, patientID, opts)
+}
+
+func (c *Client) EncounterSummary(ctx context.Context, encounterID string, opts *athenahealth.EncounterSummaryOptions) (*athenahealth.EncounterSummaryResponse, error) {
+	c.record("EncounterSummary", ctx, encounterID, opts)
+
+	if c.EncounterSummaryFunc == nil {
+		panic(unimplemented("EncounterSummary"))
+	}
+
+	return c.EncounterSummaryFunc(ctx, encounterID, opts)
+}
+
+func (c *Client) ListProviders(ctx context.Context, opts *athenahealth.ListProvidersOptions) (*athenahealth.ListProvidersResult, error) {
+	c.record("ListProviders", ctx, opts)
+
+	if c.ListProvidersFunc == nil {
+		panic(unimplemented("ListProviders"))
+	}
+
+	return c.ListProvidersFunc(ctx, opts)
+}
+
+func (c *Client) GetProvider(ctx context.Context, providerID string) (*athenahealth.Provider, error) {
+	c.record("GetProvider", ctx, providerID)
+
+	if c.GetProviderFunc == nil {
+		panic(unimplemented("GetProvider"))
+	}
+
+	return c.GetProviderFunc(ctx, providerID)
+}
+
+func (c *Client) GetSubscription(ctx context.Context, feedType athenahealth.FeedType) (*athenahealth.Subscription, error) {
+	c.record("GetSubscription", ctx, feedType)
+
+	if c.GetSubscriptionFunc == nil {
+		panic(unimplemented("GetSubscription"))
+	}
+
+	return c.GetSubscriptionFunc(ctx, feedType)
+}
+
+func (c *Client) ListSubscriptionEvents(ctx context.Context, feedType athenahealth.FeedType) ([]*athenahealth.SubscriptionEvent, error) {
+	c.record("ListSubscriptionEvents", ctx, feedType)
+
+	if c.ListSubscriptionEventsFunc == nil {
+		panic(unimplemented("ListSubscriptionEvents"))
+	}
+
+	return c.ListSubscriptionEventsFunc(ctx, feedType)
+}
+
+func (c *Client) Subscribe(ctx context.Context, feedType athenahealth.FeedType, opts *athenahealth.SubscribeOptions) error {
+	c.record("Subscribe", ctx, feedType, opts)
+
+	if c.SubscribeFunc == nil {
+		panic(unimplemented("Subscribe"))
+	}
+
+	return c.SubscribeFunc(ctx, feedType, opts)
+}
+
+func (c *Client) Unsubscribe(ctx context.Context, feedType athenahealth.FeedType, opts *athenahealth.UnsubscribeOptions) error {
+	c.record("Unsubscribe", ctx, feedType, opts)
+
+	if c.UnsubscribeFunc == nil {
+		panic(unimplemented("Unsubscribe"))
+	}
+
+	return c.UnsubscribeFunc(ctx, feedType, opts)
+}
+
+func (c *Client) EnsureSubscriptions(ctx context.Context, desired map[athenahealth.FeedType][]string) (*athenahealth.SubscriptionReport, error) {
+	c.record("EnsureSubscriptions", ctx, desired)
+
+	if c.EnsureSubscriptionsFunc == nil {
+		panic(unimplemented("EnsureSubscriptions"))
+	}
+
+	return c.EnsureSubscriptionsFunc(ctx, desired)
+}
+
+func (c *Client) ListChangedPatients(ctx context.Context, opts *athenahealth.ListChangedPatientOptions) ([]*athenahealth.Patient, error) {
+	c.record("ListChangedPatients", ctx, opts)
+
+	if c.ListChangedPatientsFunc == nil {
+		panic(unimplemented("ListChangedPatients"))
+	}
+
+	return c.ListChangedPatientsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedProviders(ctx context.Context, opts *athenahealth.ListChangedProviderOptions) ([]*athenahealth.Provider, error) {
+	c.record("ListChangedProviders", ctx, opts)
+
+	if c.ListChangedProvidersFunc == nil {
+		panic(unimplemented("ListChangedProviders"))
+	}
+
+	return c.ListChangedProvidersFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedProblems(ctx context.Context, opts *athenahealth.ListChangedProblemsOptions) ([]*athenahealth.ChangedProblem, error) {
+	c.record("ListChangedProblems", ctx, opts)
+
+	if c.ListChangedProblemsFunc == nil {
+		panic(unimplemented("ListChangedProblems"))
+	}
+
+	return c.ListChangedProblemsFunc(ctx, opts)
+}
+
+func (c *Client) ListChangedPrescriptions(ctx context.Context, options *athenahealth.ListChangedPrescriptionsOptions) (*athenahealth.ListChangedPrescriptionsResult, error) {
+	c.record("ListChangedPrescriptions", ctx, options)
+
+	if c.ListChangedPrescriptionsFunc == nil {
+		panic(unimplemented("ListChangedPrescriptions"))
+	}
+
+	return c.ListChangedPrescriptionsFunc(ctx, options)
+}
+
+func (c *Client) UpdatePrescription(ctx context.Context, departmentID int, patientID int, documentID int, opts *athenahealth.UpdatePrescriptionOptions) (*athenahealth.UpdatePrescriptionResult, error) {
+	c.record("UpdatePrescription", ctx, departmentID, patientID, documentID, opts)
+
+	if c.UpdatePrescriptionFunc == nil {
+		panic(unimplemented("UpdatePrescription"))
+	}
+
+	return c.UpdatePrescriptionFunc(ctx, departmentID, patientID, documentID, opts)
+}
+
+func (c *Client) CreateFinancialClaim(ctx context.Context, opts *athenahealth.CreateClaimOptions) ([]string, error) {
+	c.record("CreateFinancialClaim", ctx, opts)
+
+	if c.CreateFinancialClaimFunc == nil {
+		panic(unimplemented("CreateFinancialClaim"))
+	}
+
+	return c.CreateFinancialClaimFunc(ctx, opts)
+}
+
+func (c *Client) ListClaims(ctx context.Context, opts *athenahealth.ListClaimsOptions) (*athenahealth.ListClaimsResult, error) {
+	c.record("ListClaims", ctx, opts)
+
+	if c.ListClaimsFunc == nil {
+		panic(unimplemented("ListClaims"))
+	}
+
+	return c.ListClaimsFunc(ctx, opts)
+}
+
+func (c *Client) GetTelehealthInviteURL(ctx context.Context, apptID string) (*athenahealth.GetTelehealthInviteURLResult, error) {
+	c.record("GetTelehealthInviteURL", ctx, apptID)
+
+	if c.GetTelehealthInviteURLFunc == nil {
+		panic(unimplemented("GetTelehealthInviteURL"))
+	}
+
+	return c.GetTelehealthInviteURLFunc(ctx, apptID)
+}
+
+func (c *Client) AllBookedAppointments(ctx context.Context, opts *athenahealth.ListBookedAppointmentsOptions) iter.Seq2[*athenahealth.BookedAppointment, error] {
+	c.record("AllBookedAppointments", ctx, opts)
+
+	if c.AllBookedAppointmentsFunc == nil {
+		panic(unimplemented("AllBookedAppointments"))
+	}
+
+	return c.AllBookedAppointmentsFunc(ctx, opts)
+}
+
+func (c *Client) AllOpenAppointmentSlots(ctx context.Context, departmentID int, opts *athenahealth.ListOpenAppointmentSlotOptions) iter.Seq2[*athenahealth.OpenAppointmentSlot, error] {
+	c.record("AllOpenAppointmentSlots", ctx, departmentID, opts)
+
+	if c.AllOpenAppointmentSlotsFunc == nil {
+		panic(unimplemented("AllOpenAppointmentSlots"))
+	}
+
+	return c.AllOpenAppointmentSlotsFunc(ctx, departmentID, opts)
+}
+
+func (c *Client) AllClaims(ctx context.Context, opts *athenahealth.ListClaimsOptions) iter.Seq2[*athenahealth.Claim, error] {
+	c.record("AllClaims", ctx, opts)
+
+	if c.AllClaimsFunc == nil {
+		panic(unimplemented("AllClaims"))
+	}
+
+	return c.AllClaimsFunc(ctx, opts)
+}
+
+func (c *Client) AllDepartments(ctx context.Context, opts *athenahealth.ListDepartmentsOptions) iter.Seq2[*athenahealth.Department, error] {
+	c.record("AllDepartments", ctx, opts)
+
+	if c.AllDepartmentsFunc == nil {
+		panic(unimplemented("AllDepartments"))
+	}
+
+	return c.AllDepartmentsFunc(ctx, opts)
+}
+
+func (c *Client) AllAdminDocuments(ctx context.Context, patientID string, opts *athenahealth.ListAdminDocumentsOptions) iter.Seq2[*athenahealth.AdminDocument, error] {
+	c.record("AllAdminDocuments", ctx, patientID, opts)
+
+	if c.AllAdminDocumentsFunc == nil {
+		panic(unimplemented("AllAdminDocuments"))
+	}
+
+	return c.AllAdminDocumentsFunc(ctx, patientID, opts)
+}
+
+func (c *Client) AllEncounterDocuments(ctx context.Context, departmentID string, patientID string, opts *athenahealth.ListEncounterDocumentsOptions) iter.Seq2[*athenahealth.EncounterDocument, error] {
+	c.record("AllEncounterDocuments", ctx, departmentID, patientID, opts)
+
+	if c.AllEncounterDocumentsFunc == nil {
+		panic(unimplemented("AllEncounterDocuments"))
+	}
+
+	return c.AllEncounterDocumentsFunc(ctx, departmentID, patientID, opts)
+}
+
+func (c *Client) AllPatientInsurancePackages(ctx context.Context, opts *athenahealth.ListPatientInsurancePackagesOptions) iter.Seq2[*athenahealth.InsurancePackage, error] {
+	c.record("AllPatientInsurancePackages", ctx, opts)
+
+	if c.AllPatientInsurancePackagesFunc == nil {
+		panic(unimplemented("AllPatientInsurancePackages"))
+	}
+
+	return c.AllPatientInsurancePackagesFunc(ctx, opts)
+}
+
+func (c *Client) AllLabResults(ctx context.Context, patientID string, departmentID string, opts *athenahealth.ListLabResultsOptions) iter.Seq2[*athenahealth.LabResult, error] {
+	c.record("AllLabResults", ctx, patientID, departmentID, opts)
+
+	if c.AllLabResultsFunc == nil {
+		panic(unimplemented("AllLabResults"))
+	}
+
+	return c.AllLabResultsFunc(ctx, patientID, departmentID, opts)
+}
+
+func (c *Client) AllChangedLabResults(ctx context.Context, opts *athenahealth.ListChangedLabResultsOptions) iter.Seq2[*athenahealth.ChangedLabResult, error] {
+	c.record("AllChangedLabResults", ctx, opts)
+
+	if c.AllChangedLabResultsFunc == nil {
+		panic(unimplemented("AllChangedLabResults"))
+	}
+
+	return c.AllChangedLabResultsFunc(ctx, opts)
+}
+
+func (c *Client) AllPatients(ctx context.Context, opts *athenahealth.ListPatientsOptions) iter.Seq2[*athenahealth.Patient, error] {
+	c.record("AllPatients", ctx, opts)
+
+	if c.AllPatientsFunc == nil {
+		panic(unimplemented("AllPatients"))
+	}
+
+	return c.AllPatientsFunc(ctx, opts)
+}
+
+func (c *Client) AllPatientsMatchingCustomField(ctx context.Context, opts *athenahealth.ListPatientsMatchingCustomFieldOptions) iter.Seq2[*athenahealth.Patient, error] {
+	c.record("AllPatientsMatchingCustomField", ctx, opts)
+
+	if c.AllPatientsMatchingCustomFieldFunc == nil {
+		panic(unimplemented("AllPatientsMatchingCustomField"))
+	}
+
+	return c.AllPatientsMatchingCustomFieldFunc(ctx, opts)
+}
+
+func (c *Client) AllChangedPrescriptions(ctx context.Context, opts *athenahealth.ListChangedPrescriptionsOptions) iter.Seq2[*athenahealth.ChangedPrescription, error] {
+	c.record("AllChangedPrescriptions", ctx, opts)
+
+	if c.AllChangedPrescriptionsFunc == nil {
+		panic(unimplemented("AllChangedPrescriptions"))
+	}
+
+	return c.AllChangedPrescriptionsFunc(ctx, opts)
+}
+
+func (c *Client) AllProviders(ctx context.Context, opts *athenahealth.ListProvidersOptions) iter.Seq2[*athenahealth.Provider, error] {
+	c.record("AllProviders", ctx, opts)
+
+	if c.AllProvidersFunc == nil {
+		panic(unimplemented("AllProviders"))
+	}
+
+	return c.AllProvidersFunc(ctx, opts)
+}
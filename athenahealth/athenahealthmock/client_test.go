@@ -0,0 +1,83 @@
+package athenahealthmock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+
+	expectedErr := errors.New("not found")
+
+	client := &Client{
+		GetPatientFunc: func(ctx context.Context, patientID string, opts *athenahealth.GetPatientOptions) (*athenahealth.Patient, error) {
+			if patientID == "404" {
+				return nil, expectedErr
+			}
+
+			return &athenahealth.Patient{PatientID: patientID}, nil
+		},
+	}
+
+	var athenaClient athenahealth.Client = client
+
+	ctx := context.Background()
+	opts := &athenahealth.GetPatientOptions{ShowInsurance: true}
+
+	patient, err := athenaClient.GetPatient(ctx, "1", opts)
+	assert.NoError(err)
+	assert.Equal("1", patient.PatientID)
+
+	_, err = athenaClient.GetPatient(ctx, "404", nil)
+	assert.ErrorIs(err, expectedErr)
+
+	assert.True(client.AssertCallCount(t, "GetPatient", 2))
+	assert.True(client.AssertNotCalled(t, "ListPatients"))
+
+	calls := client.CallsTo("GetPatient")
+	assert.Equal([]any{ctx, "1", opts}, calls[0].Args)
+	assert.Equal("404", calls[1].Args[1])
+}
+
+func TestClient_unimplemented(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{}
+
+	assert.PanicsWithValue("athenahealthmock: GetDepartment called but GetDepartmentFunc is nil", func() {
+		_, _ = client.GetDepartment(context.Background(), "1")
+	})
+
+	assert.Equal(1, client.CallCount("GetDepartment"))
+}
+
+func TestClient_concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &Client{
+		AppointmentCheckInFunc: func(ctx context.Context, apptID string) error {
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_ = client.AppointmentCheckIn(context.Background(), "1")
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(20, client.CallCount("AppointmentCheckIn"))
+}
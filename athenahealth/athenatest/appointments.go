@@ -0,0 +1,497 @@
+package athenatest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// AddOpenSlot adds an open appointment slot to the server. If slot.AppointmentID is zero, an ID is
+// assigned. Date is mm/dd/yyyy and StartTime is HH:MM. The slot's appointment ID is returned.
+func (s *Server) AddOpenSlot(slot *athenahealth.OpenAppointmentSlot) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	appt := &athenahealth.BookedAppointment{
+		AppointmentID:              itoa(slot.AppointmentID),
+		AppointmentStatus:          athenahealth.AppointmentStatusOpen,
+		AppointmentType:            slot.AppointmentType,
+		AppointmentTypeID:          itoa(slot.AppointmentTypeID),
+		Date:                       slot.Date,
+		DepartmentID:               itoa(slot.DepartmentID),
+		Duration:                   slot.Duration,
+		FrozenYN:                   yn(slot.Frozen),
+		PatientAppointmentTypeName: slot.PatientAppointmentTypeName,
+		ProviderID:                 itoa(slot.ProviderID),
+		StartTime:                  slot.StartTime,
+	}
+
+	if len(appt.AppointmentID) == 0 {
+		appt.AppointmentID = s.id()
+	}
+
+	s.appointments = append(s.appointments, appt)
+
+	id, _ := strconv.Atoi(appt.AppointmentID)
+
+	return id
+}
+
+// Appointment returns a copy of the appointment or open slot with the given ID, or nil if there is
+// none.
+func (s *Server) Appointment(id string) *athenahealth.BookedAppointment {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	appt := s.appointment(id)
+	if appt == nil {
+		return nil
+	}
+
+	return clone(appt)
+}
+
+func (s *Server) appointment(id string) *athenahealth.BookedAppointment {
+	for _, appt := range s.appointments {
+		if appt.AppointmentID == id {
+			return appt
+		}
+	}
+
+	return nil
+}
+
+// changeAppointment records a change to appt and publishes it to the changed appointments feed.
+func (s *Server) changeAppointment(appt *athenahealth.BookedAppointment) {
+	appt.LastModified = s.now().Format(datetimeFormat)
+	appt.LastModifiedBy = "API-athenatest"
+
+	s.changedAppointments.publish(appt)
+}
+
+func openSlot(appt *athenahealth.BookedAppointment) *athenahealth.OpenAppointmentSlot {
+	return &athenahealth.OpenAppointmentSlot{
+		AppointmentID:              atoi(appt.AppointmentID),
+		AppointmentType:            appt.AppointmentType,
+		AppointmentTypeID:          atoi(appt.AppointmentTypeID),
+		Date:                       appt.Date,
+		DepartmentID:               atoi(appt.DepartmentID),
+		Duration:                   appt.Duration,
+		Frozen:                     appt.FrozenYN == "Y",
+		PatientAppointmentTypeName: appt.PatientAppointmentTypeName,
+		ProviderID:                 atoi(appt.ProviderID),
+		StartTime:                  appt.StartTime,
+	}
+}
+
+func (s *Server) listOpenSlots(w http.ResponseWriter, r *http.Request) {
+	if len(r.Form.Get("departmentid")) == 0 {
+		writeError(w, http.StatusBadRequest, "The departmentid field is required.")
+		return
+	}
+
+	providerIDs := splitParam(r, "providerid")
+	showFrozen, _ := strconv.ParseBool(r.Form.Get("showfrozenslots"))
+
+	slots := []*athenahealth.OpenAppointmentSlot{}
+
+	for _, appt := range s.appointments {
+		if appt.AppointmentStatus != athenahealth.AppointmentStatusOpen {
+			continue
+		}
+
+		if !slices.Contains(splitParam(r, "departmentid"), appt.DepartmentID) {
+			continue
+		}
+
+		if len(providerIDs) > 0 && !slices.Contains(providerIDs, appt.ProviderID) {
+			continue
+		}
+
+		if !matchParam(r, "appointmenttypeid", appt.AppointmentTypeID) || !inDateRange(r, appt.Date) {
+			continue
+		}
+
+		if appt.FrozenYN == "Y" && !showFrozen {
+			continue
+		}
+
+		slots = append(slots, openSlot(appt))
+	}
+
+	slots, pagination := paginate(r, slots)
+
+	writeJSON(w, http.StatusOK, struct {
+		Appointments []*athenahealth.OpenAppointmentSlot `json:"appointments"`
+		athenahealth.PaginationResponse
+	}{slots, pagination})
+}
+
+func (s *Server) createOpenSlots(w http.ResponseWriter, r *http.Request) {
+	if s.department(r.Form.Get("departmentid")) == nil {
+		writeError(w, http.StatusBadRequest, "Invalid department ID.")
+		return
+	}
+
+	if s.provider(r.Form.Get("providerid")) == nil {
+		writeError(w, http.StatusBadRequest, "Invalid provider ID.")
+		return
+	}
+
+	if _, ok := parseDate(r.Form.Get("appointmentdate")); !ok {
+		writeError(w, http.StatusBadRequest, "The appointmentdate field is required.")
+		return
+	}
+
+	times := splitParam(r, "appointmenttime")
+	if len(times) == 0 {
+		writeError(w, http.StatusBadRequest, "The appointmenttime field is required.")
+		return
+	}
+
+	ids := make(map[string]string, len(times))
+
+	for _, startTime := range times {
+		appt := &athenahealth.BookedAppointment{
+			AppointmentID:     s.id(),
+			AppointmentStatus: athenahealth.AppointmentStatusOpen,
+			AppointmentTypeID: r.Form.Get("appointmenttypeid"),
+			Date:              r.Form.Get("appointmentdate"),
+			DepartmentID:      r.Form.Get("departmentid"),
+			FrozenYN:          "N",
+			ProviderID:        r.Form.Get("providerid"),
+			StartTime:         startTime,
+		}
+
+		s.appointments = append(s.appointments, appt)
+		ids[appt.AppointmentID] = startTime
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"appointmentids": ids,
+	})
+}
+
+func (s *Server) listBookedAppointments(w http.ResponseWriter, r *http.Request) {
+	if len(r.Form.Get("startdate")) == 0 || len(r.Form.Get("enddate")) == 0 {
+		writeError(w, http.StatusBadRequest, "The startdate and enddate fields are required.")
+		return
+	}
+
+	appts := []*athenahealth.BookedAppointment{}
+
+	for _, appt := range s.appointments {
+		if appt.AppointmentStatus == athenahealth.AppointmentStatusOpen {
+			continue
+		}
+
+		if !matchParam(r, "departmentid", appt.DepartmentID) ||
+			!matchParam(r, "providerid", appt.ProviderID) ||
+			!matchParam(r, "patientid", appt.PatientID) ||
+			!matchParam(r, "appointmenttypeid", appt.AppointmentTypeID) ||
+			!matchParam(r, "appointmentstatus", appt.AppointmentStatus.String()) ||
+			!inDateRange(r, appt.Date) {
+			continue
+		}
+
+		appts = append(appts, appt)
+	}
+
+	appts, pagination := paginate(r, appts)
+
+	writeJSON(w, http.StatusOK, struct {
+		Appointments []*athenahealth.BookedAppointment `json:"appointments"`
+		athenahealth.PaginationResponse
+	}{appts, pagination})
+}
+
+func (s *Server) getAppointment(w http.ResponseWriter, r *http.Request) {
+	appt := s.appointment(r.PathValue("appointmentid"))
+	if appt == nil {
+		writeError(w, http.StatusNotFound, "The appointment is not available.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []*athenahealth.Appointment{{
+		AppointmentID:              appt.AppointmentID,
+		AppointmentStatus:          appt.AppointmentStatus,
+		AppointmentType:            appt.AppointmentType,
+		AppointmentTypeID:          appt.AppointmentTypeID,
+		ChargeEntryNotRequired:     appt.ChargeEntryNotRequired,
+		Date:                       appt.Date,
+		DepartmentID:               appt.DepartmentID,
+		Duration:                   appt.Duration,
+		EncounterID:                appt.EncounterID,
+		PatientAppointmentTypeName: appt.PatientAppointmentTypeName,
+		PatientID:                  appt.PatientID,
+		ProviderID:                 appt.ProviderID,
+		RenderingProviderID:        appt.RenderingProviderID,
+		StartTime:                  appt.StartTime,
+	}})
+}
+
+// book books the open slot appt for patientID.
+func (s *Server) book(appt *athenahealth.BookedAppointment, patientID string, r *http.Request) {
+	appt.AppointmentStatus = athenahealth.AppointmentStatusFuture
+	appt.PatientID = patientID
+	appt.ScheduledBy = "API-athenatest"
+	appt.ScheduledDatetime = s.now().Format(datetimeFormat)
+
+	if appointmentTypeID := r.Form.Get("appointmenttypeid"); len(appointmentTypeID) > 0 {
+		appt.AppointmentTypeID = appointmentTypeID
+	}
+
+	if bookingNote := r.Form.Get("bookingnote"); len(bookingNote) > 0 {
+		appt.AppointmentNotes = append(appt.AppointmentNotes, athenahealth.AppointmentNotes{
+			ID:   atoi(s.id()),
+			Text: bookingNote,
+		})
+	}
+
+	if urgent, _ := strconv.ParseBool(r.Form.Get("urgent")); urgent {
+		appt.UrgentYN = "Y"
+	}
+
+	s.changeAppointment(appt)
+}
+
+// bookableSlot returns the open slot with the given ID, or writes an error response and returns
+// nil if it does not exist or is not open.
+func (s *Server) bookableSlot(w http.ResponseWriter, id string) *athenahealth.BookedAppointment {
+	appt := s.appointment(id)
+	if appt == nil {
+		writeError(w, http.StatusNotFound, "The appointment is not available.")
+		return nil
+	}
+
+	if appt.AppointmentStatus != athenahealth.AppointmentStatusOpen || appt.FrozenYN == "Y" {
+		writeError(w, http.StatusConflict, "The appointment ID is already booked or is not marked as being available to be scheduled.")
+		return nil
+	}
+
+	return appt
+}
+
+func (s *Server) bookAppointment(w http.ResponseWriter, r *http.Request) {
+	if s.patient(r.Form.Get("patientid")) == nil {
+		writeError(w, http.StatusBadRequest, "Invalid patient ID.")
+		return
+	}
+
+	appt := s.bookableSlot(w, r.PathValue("appointmentid"))
+	if appt == nil {
+		return
+	}
+
+	s.book(appt, r.Form.Get("patientid"), r)
+
+	writeJSON(w, http.StatusOK, []*athenahealth.BookedAppointment{appt})
+}
+
+// bookedAppointment returns the booked appointment with the given ID, or writes an error response
+// and returns nil if it does not exist or has not been booked for the patient in r.
+func (s *Server) bookedAppointment(w http.ResponseWriter, r *http.Request) *athenahealth.BookedAppointment {
+	appt := s.appointment(r.PathValue("appointmentid"))
+	if appt == nil {
+		writeError(w, http.StatusNotFound, "The appointment is not available.")
+		return nil
+	}
+
+	if appt.AppointmentStatus != athenahealth.AppointmentStatusFuture {
+		writeError(w, http.StatusBadRequest, "The appointment is not a future booked appointment.")
+		return nil
+	}
+
+	if !matchParam(r, "patientid", appt.PatientID) {
+		writeError(w, http.StatusBadRequest, "The appointment is not booked for this patient.")
+		return nil
+	}
+
+	return appt
+}
+
+func (s *Server) cancel(appt *athenahealth.BookedAppointment, r *http.Request) {
+	appt.AppointmentStatus = athenahealth.AppointmentStatusCancelled
+	appt.CancelledBy = "API-athenatest"
+	appt.CancelledDatetime = s.now().Format(datetimeFormat)
+	appt.CancelReasonID = r.Form.Get("appointmentcancelreasonid")
+}
+
+func (s *Server) rescheduleAppointment(w http.ResponseWriter, r *http.Request) {
+	if s.patient(r.Form.Get("patientid")) == nil {
+		writeError(w, http.StatusBadRequest, "Invalid patient ID.")
+		return
+	}
+
+	appt := s.bookedAppointment(w, r)
+	if appt == nil {
+		return
+	}
+
+	newAppt := s.bookableSlot(w, r.Form.Get("newappointmentid"))
+	if newAppt == nil {
+		return
+	}
+
+	s.cancel(appt, r)
+	appt.RescheduledAppointmentID = newAppt.AppointmentID
+	s.changeAppointment(appt)
+
+	if len(r.Form.Get("appointmenttypeid")) == 0 {
+		r.Form.Set("appointmenttypeid", appt.AppointmentTypeID)
+	}
+
+	s.book(newAppt, appt.PatientID, r)
+
+	writeJSON(w, http.StatusOK, []*athenahealth.RescheduleAppointmentResult{{
+		AppointmentID:              newAppt.AppointmentID,
+		AppointmentStatus:          newAppt.AppointmentStatus,
+		AppointmentType:            newAppt.AppointmentType,
+		AppointmentTypeID:          newAppt.AppointmentTypeID,
+		Date:                       newAppt.Date,
+		DepartmentID:               newAppt.DepartmentID,
+		Duration:                   newAppt.Duration,
+		FrozenYN:                   newAppt.FrozenYN,
+		PatientAppointmentTypeName: newAppt.PatientAppointmentTypeName,
+		PatientID:                  newAppt.PatientID,
+		ProviderID:                 newAppt.ProviderID,
+		StartTime:                  newAppt.StartTime,
+		UrgentYN:                   newAppt.UrgentYN,
+	}})
+}
+
+func (s *Server) cancelAppointment(w http.ResponseWriter, r *http.Request) {
+	if len(r.Form.Get("patientid")) == 0 {
+		writeError(w, http.StatusBadRequest, "The patientid field is required.")
+		return
+	}
+
+	appt := s.bookedAppointment(w, r)
+	if appt == nil {
+		return
+	}
+
+	s.cancel(appt, r)
+	s.changeAppointment(appt)
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status": string(appt.AppointmentStatus),
+	})
+}
+
+func (s *Server) startCheckIn(w http.ResponseWriter, r *http.Request) {
+	appt := s.bookedAppointment(w, r)
+	if appt == nil {
+		return
+	}
+
+	appt.StartCheckIn = s.now().Format(datetimeFormat)
+	s.changeAppointment(appt)
+
+	writeSuccess(w)
+}
+
+func (s *Server) cancelCheckIn(w http.ResponseWriter, r *http.Request) {
+	appt := s.bookedAppointment(w, r)
+	if appt == nil {
+		return
+	}
+
+	if len(appt.StartCheckIn) == 0 {
+		writeError(w, http.StatusBadRequest, "Check-in has not been started for this appointment.")
+		return
+	}
+
+	appt.StartCheckIn = ""
+	s.changeAppointment(appt)
+
+	writeSuccess(w)
+}
+
+func (s *Server) checkIn(w http.ResponseWriter, r *http.Request) {
+	appt := s.bookedAppointment(w, r)
+	if appt == nil {
+		return
+	}
+
+	now := s.now().Format(datetimeFormat)
+
+	if len(appt.StartCheckIn) == 0 {
+		appt.StartCheckIn = now
+	}
+
+	appt.AppointmentStatus = athenahealth.AppointmentStatusCheckedIn
+	appt.CheckInDateTime = now
+	appt.StopCheckIn = now
+	appt.EncounterID = s.id()
+	s.changeAppointment(appt)
+
+	writeSuccess(w)
+}
+
+func (s *Server) checkOut(w http.ResponseWriter, r *http.Request) {
+	appt := s.appointment(r.PathValue("appointmentid"))
+	if appt == nil {
+		writeError(w, http.StatusNotFound, "The appointment is not available.")
+		return
+	}
+
+	if appt.AppointmentStatus != athenahealth.AppointmentStatusCheckedIn {
+		writeEnvelopeError(w, "The appointment is not checked in.")
+		return
+	}
+
+	appt.AppointmentStatus = athenahealth.AppointmentStatusCheckedOut
+	appt.CheckOutDateTime = s.now().Format(datetimeFormat)
+	s.changeAppointment(appt)
+
+	writeSuccess(w)
+}
+
+func (s *Server) listChangedAppointments(w http.ResponseWriter, r *http.Request) {
+	appts := s.changedAppointments.list(r, s.now(), s.location, func(appt *athenahealth.BookedAppointment) bool {
+		return matchParam(r, "departmentid", appt.DepartmentID) &&
+			matchParam(r, "patientid", appt.PatientID) &&
+			matchParam(r, "providerid", appt.ProviderID)
+	})
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"appointments": appts,
+		"totalcount":   len(appts),
+	})
+}
+
+// splitParam returns the comma separated values of the request parameter name.
+func splitParam(r *http.Request, name string) []string {
+	param := r.Form.Get(name)
+	if len(param) == 0 {
+		return nil
+	}
+
+	return strings.Split(param, ",")
+}
+
+func itoa(i int) string {
+	if i == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(i)
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+
+	return i
+}
+
+func yn(b bool) string {
+	if b {
+		return "Y"
+	}
+
+	return "N"
+}
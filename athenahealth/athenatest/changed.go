@@ -0,0 +1,98 @@
+package athenatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// change is a record published to a changed feed. processedAt is zero until the record is
+// returned by a request without leaveunprocessed.
+type change[T any] struct {
+	record      T
+	processedAt time.Time
+}
+
+// feed is an athenahealth changed feed.
+type feed[T any] struct {
+	changes []*change[T]
+}
+
+// publish adds a copy of record to the feed, so later changes to record are not reflected in it.
+func (f *feed[T]) publish(record T) {
+	f.changes = append(f.changes, &change[T]{
+		record: clone(record),
+	})
+}
+
+// list returns the records requested by r: the unprocessed records, which are marked processed
+// unless r sets leaveunprocessed, or the records processed between showprocessedstartdatetime and
+// showprocessedenddatetime. Records for which match returns false are skipped and left
+// unprocessed.
+func (f *feed[T]) list(r *http.Request, now time.Time, location *time.Location, match func(T) bool) []T {
+	records := []T{}
+
+	start, startErr := time.ParseInLocation(datetimeFormat, r.Form.Get("showprocessedstartdatetime"), location)
+	end, endErr := time.ParseInLocation(datetimeFormat, r.Form.Get("showprocessedenddatetime"), location)
+
+	if startErr == nil || endErr == nil {
+		for _, c := range f.changes {
+			if c.processedAt.IsZero() || !match(c.record) {
+				continue
+			}
+
+			if startErr == nil && c.processedAt.Before(start) {
+				continue
+			}
+
+			// Datetimes are only precise to the second, so the end is inclusive of it.
+			if endErr == nil && !c.processedAt.Before(end.Add(time.Second)) {
+				continue
+			}
+
+			records = append(records, c.record)
+		}
+
+		return records
+	}
+
+	leaveUnprocessed, _ := strconv.ParseBool(r.Form.Get("leaveunprocessed"))
+
+	for _, c := range f.changes {
+		if !c.processedAt.IsZero() || !match(c.record) {
+			continue
+		}
+
+		records = append(records, c.record)
+
+		if !leaveUnprocessed {
+			c.processedAt = now
+		}
+	}
+
+	return records
+}
+
+func clone[T any](v T) T {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	var c T
+
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// matchParam returns true if the request parameter name is unset or equal to value.
+func matchParam(r *http.Request, name, value string) bool {
+	param := r.Form.Get(name)
+
+	return len(param) == 0 || param == value
+}
@@ -0,0 +1,52 @@
+package athenatest
+
+import (
+	"net/http"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// AddDepartment adds a department to the server. If department.DepartmentID is empty, an ID is
+// assigned. The department's ID is returned.
+func (s *Server) AddDepartment(department *athenahealth.Department) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	department = clone(department)
+	if len(department.DepartmentID) == 0 {
+		department.DepartmentID = s.id()
+	}
+
+	s.departments = append(s.departments, department)
+
+	return department.DepartmentID
+}
+
+func (s *Server) department(id string) *athenahealth.Department {
+	for _, department := range s.departments {
+		if department.DepartmentID == id {
+			return department
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, pagination := paginate(r, s.departments)
+
+	writeJSON(w, http.StatusOK, struct {
+		Departments []*athenahealth.Department `json:"departments"`
+		athenahealth.PaginationResponse
+	}{departments, pagination})
+}
+
+func (s *Server) getDepartment(w http.ResponseWriter, r *http.Request) {
+	department := s.department(r.PathValue("departmentid"))
+	if department == nil {
+		writeError(w, http.StatusNotFound, "The department is not available.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []*athenahealth.Department{department})
+}
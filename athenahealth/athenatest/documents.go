@@ -0,0 +1,110 @@
+package athenatest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// Document is a document added to a patient's chart.
+type Document struct {
+	athenahealth.AdminDocument
+
+	DocumentSubclass string
+	Contents         []byte
+}
+
+// Documents returns copies of the documents added to the patient's chart.
+func (s *Server) Documents(patientID string) []*Document {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	documents := make([]*Document, 0, len(s.documents[patientID]))
+	for _, document := range s.documents[patientID] {
+		c := *document
+		c.Contents = slices.Clone(document.Contents)
+		documents = append(documents, &c)
+	}
+
+	return documents
+}
+
+func (s *Server) listAdminDocuments(w http.ResponseWriter, r *http.Request) {
+	if s.patient(r.PathValue("patientid")) == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	documents := []*athenahealth.AdminDocument{}
+
+	for _, document := range s.documents[r.PathValue("patientid")] {
+		if document.DocumentClass != "ADMIN" || !matchParam(r, "departmentid", document.DepartmentID) {
+			continue
+		}
+
+		documents = append(documents, &document.AdminDocument)
+	}
+
+	documents, pagination := paginate(r, documents)
+
+	writeJSON(w, http.StatusOK, struct {
+		AdminDocuments []*athenahealth.AdminDocument `json:"admins"`
+		athenahealth.PaginationResponse
+	}{documents, pagination})
+}
+
+func (s *Server) addDocument(w http.ResponseWriter, r *http.Request) {
+	patientID := r.PathValue("patientid")
+	if s.patient(patientID) == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	subclass := r.Form.Get("documentsubclass")
+	if len(subclass) == 0 {
+		writeError(w, http.StatusBadRequest, "The documentsubclass field is required.")
+		return
+	}
+
+	contents, err := base64.StdEncoding.DecodeString(r.Form.Get("attachmentcontents"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The attachmentcontents field is not valid base64.")
+		return
+	}
+
+	class, _, _ := strings.Cut(subclass, "_")
+	now := s.now()
+
+	document := &Document{
+		AdminDocument: athenahealth.AdminDocument{
+			AdminID:              atoi(s.id()),
+			CreatedDate:          now.Format(dateFormat),
+			CreatedDateTime:      now.Format(datetimeFormat),
+			CreatedUser:          "API-athenatest",
+			DepartmentID:         r.Form.Get("departmentid"),
+			DocumentClass:        class,
+			DocumentDate:         now.Format(dateFormat),
+			DocumentSource:       "INTERFACE",
+			InternalNote:         r.Form.Get("internalnote"),
+			LastModifiedDate:     now.Format(dateFormat),
+			LastModifiedDatetime: now.Format(datetimeFormat),
+			ProviderID:           atoi(r.Form.Get("providerid")),
+			Status:               "REVIEW",
+		},
+		DocumentSubclass: subclass,
+		Contents:         contents,
+	}
+
+	if autoClose := r.Form.Get("autoclose"); autoClose == "true" || autoClose == "1" {
+		document.Status = "CLOSED"
+	}
+
+	s.documents[patientID] = append(s.documents[patientID], document)
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"documentid": itoa(document.AdminID),
+	})
+}
@@ -0,0 +1,48 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_AddDocument(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	patientID := srv.AddPatient(&athenahealth.Patient{})
+	client := srv.NewClient()
+
+	departmentID := 1
+	documentID, err := client.AddDocument(ctx, patientID, &athenahealth.AddDocumentOptions{
+		AttachmentContents: []byte("hello"),
+		DepartmentID:       &departmentID,
+		DocumentSubclass:   "ADMIN_CONSENT",
+	})
+	assert.NoError(err)
+	assert.NotEmpty(documentID)
+
+	_, err = client.AddDocument(ctx, patientID, &athenahealth.AddDocumentOptions{
+		AttachmentContents: []byte("note"),
+		DocumentSubclass:   "CLINICALDOCUMENT_OPERATIVENOTE",
+	})
+	assert.NoError(err)
+
+	res, err := client.ListAdminDocuments(ctx, patientID, nil)
+	assert.NoError(err)
+	assert.Len(res.AdminDocuments, 1)
+	assert.Equal(documentID, itoa(res.AdminDocuments[0].AdminID))
+
+	documents := srv.Documents(patientID)
+	assert.Len(documents, 2)
+	assert.Equal("ADMIN_CONSENT", documents[0].DocumentSubclass)
+	assert.Equal([]byte("hello"), documents[0].Contents)
+
+	_, err = client.AddDocument(ctx, "404", &athenahealth.AddDocumentOptions{DocumentSubclass: "ADMIN_CONSENT"})
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
@@ -0,0 +1,135 @@
+package athenatest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+func (s *Server) insurance(patientID, insuranceID string) *athenahealth.InsurancePackage {
+	for _, insurance := range s.insurances[patientID] {
+		if insurance.InsuranceID == insuranceID {
+			return insurance
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listInsurances(w http.ResponseWriter, r *http.Request) {
+	if s.patient(r.PathValue("patientid")) == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	showCancelled, _ := strconv.ParseBool(r.Form.Get("showcancelled"))
+
+	insurances := []*athenahealth.InsurancePackage{}
+
+	for _, insurance := range s.insurances[r.PathValue("patientid")] {
+		if len(insurance.Cancelled) > 0 && !showCancelled {
+			continue
+		}
+
+		insurances = append(insurances, insurance)
+	}
+
+	insurances, pagination := paginate(r, insurances)
+
+	writeJSON(w, http.StatusOK, struct {
+		Insurances []*athenahealth.InsurancePackage `json:"insurances"`
+		athenahealth.PaginationResponse
+	}{insurances, pagination})
+}
+
+func (s *Server) createInsurance(w http.ResponseWriter, r *http.Request) {
+	patientID := r.PathValue("patientid")
+	if s.patient(patientID) == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	packageID := atoi(r.Form.Get("insurancepackageid"))
+	if packageID == 0 {
+		writeError(w, http.StatusBadRequest, "The insurancepackageid field is required.")
+		return
+	}
+
+	sequenceNumber := atoi(r.Form.Get("sequencenumber"))
+	if sequenceNumber == 0 {
+		sequenceNumber = 1
+	}
+
+	for _, insurance := range s.insurances[patientID] {
+		if len(insurance.Cancelled) == 0 && insurance.SequenceNumber == sequenceNumber {
+			writeError(w, http.StatusBadRequest, "The patient already has an insurance package with this sequence number.")
+			return
+		}
+	}
+
+	insurance := &athenahealth.InsurancePackage{
+		EligibilityStatus:              "Unverified",
+		InsuranceID:                    s.id(),
+		InsuranceIDNumber:              r.Form.Get("insuranceidnumber"),
+		InsurancePackageID:             packageID,
+		InsurancePolicyHolderdDOB:      r.Form.Get("insurancepolicyholderdob"),
+		InsurancePolicyHolderFirstName: r.Form.Get("insurancepolicyholderfirstname"),
+		InsurancePolicyHolderLastName:  r.Form.Get("insurancepolicyholderlastname"),
+		InsurancePolicyHolderSex:       r.Form.Get("insurancepolicyholdersex"),
+		SequenceNumber:                 sequenceNumber,
+	}
+
+	insurance.InsurancePolicyHolder = insurance.InsurancePolicyHolderFirstName + " " + insurance.InsurancePolicyHolderLastName
+
+	s.insurances[patientID] = append(s.insurances[patientID], insurance)
+
+	writeJSON(w, http.StatusOK, []*athenahealth.InsurancePackage{insurance})
+}
+
+func (s *Server) updateInsurance(w http.ResponseWriter, r *http.Request) {
+	insurance := s.insurance(r.PathValue("patientid"), r.PathValue("insuranceid"))
+	if insurance == nil {
+		writeError(w, http.StatusNotFound, "The insurance package is not available.")
+		return
+	}
+
+	fields := map[string]*string{
+		"expirationdate":                 &insurance.ExpirationDate,
+		"insuranceidnumber":              &insurance.InsuranceIDNumber,
+		"insurancepolicyholderdob":       &insurance.InsurancePolicyHolderdDOB,
+		"insurancepolicyholderfirstname": &insurance.InsurancePolicyHolderFirstName,
+		"insurancepolicyholderlastname":  &insurance.InsurancePolicyHolderLastName,
+		"insurancepolicyholdersex":       &insurance.InsurancePolicyHolderSex,
+	}
+
+	for name, field := range fields {
+		if values, ok := r.Form[name]; ok {
+			*field = values[0]
+		}
+	}
+
+	if values, ok := r.Form["newsequencenumber"]; ok {
+		insurance.SequenceNumber = atoi(values[0])
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"message": "Insurance package updated.",
+	})
+}
+
+func (s *Server) deleteInsurance(w http.ResponseWriter, r *http.Request) {
+	insurance := s.insurance(r.PathValue("patientid"), r.PathValue("insuranceid"))
+	if insurance == nil || len(insurance.Cancelled) > 0 {
+		writeError(w, http.StatusNotFound, "The insurance package is not available.")
+		return
+	}
+
+	insurance.Cancelled = s.now().Format(dateFormat)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"message": "Insurance package deleted.",
+	})
+}
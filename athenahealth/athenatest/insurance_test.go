@@ -0,0 +1,56 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_insurance(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	patientID := srv.AddPatient(&athenahealth.Patient{})
+	client := srv.NewClient()
+
+	insurance, err := client.CreatePatientInsurancePackage(ctx, &athenahealth.CreatePatientInsurancePackageOptions{
+		PatientID:                      patientID,
+		InsurancePackageID:             100,
+		InsuranceIDNumber:              "ABC123",
+		InsurancePolicyHolderFirstName: "John",
+		InsurancePolicyHolderLastName:  "Doe",
+		InsurancePolicyHolderDOB:       time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		SequenceNumber:                 1,
+	})
+	assert.NoError(err)
+	assert.Equal("ABC123", insurance.InsuranceIDNumber)
+
+	idNumber := "XYZ789"
+	err = client.UpdatePatientInsurancePackage(ctx, &athenahealth.UpdatePatientInsurancePackageOptions{
+		PatientID:         patientID,
+		InsuranceID:       insurance.InsuranceID,
+		InsuranceIDNumber: &idNumber,
+	})
+	assert.NoError(err)
+
+	res, err := client.ListPatientInsurancePackages(ctx, &athenahealth.ListPatientInsurancePackagesOptions{PatientID: patientID})
+	assert.NoError(err)
+	if assert.Len(res.InsurancePackages, 1) {
+		assert.Equal(idNumber, res.InsurancePackages[0].InsuranceIDNumber)
+	}
+
+	assert.NoError(client.DeletePatientInsurancePackage(ctx, patientID, insurance.InsuranceID, "no longer covered"))
+
+	res, err = client.ListPatientInsurancePackages(ctx, &athenahealth.ListPatientInsurancePackagesOptions{PatientID: patientID})
+	assert.NoError(err)
+	assert.Empty(res.InsurancePackages)
+
+	err = client.DeletePatientInsurancePackage(ctx, patientID, insurance.InsuranceID, "")
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
@@ -0,0 +1,209 @@
+package athenatest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// AddPatient adds a patient to the server and publishes it to the changed patients feed. If
+// patient.PatientID is empty, an ID is assigned. The patient's ID is returned.
+func (s *Server) AddPatient(patient *athenahealth.Patient) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.addPatient(clone(patient))
+}
+
+func (s *Server) addPatient(patient *athenahealth.Patient) string {
+	if len(patient.PatientID) == 0 {
+		patient.PatientID = s.id()
+	}
+
+	if len(patient.Status) == 0 {
+		patient.Status = "active"
+	}
+
+	s.patients = append(s.patients, patient)
+	s.changedPatients.publish(patient)
+
+	return patient.PatientID
+}
+
+// Patient returns a copy of the patient with the given ID, or nil if there is none.
+func (s *Server) Patient(id string) *athenahealth.Patient {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	patient := s.patient(id)
+	if patient == nil {
+		return nil
+	}
+
+	return clone(patient)
+}
+
+func (s *Server) patient(id string) *athenahealth.Patient {
+	for _, patient := range s.patients {
+		if patient.PatientID == id {
+			return patient
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listPatients(w http.ResponseWriter, r *http.Request) {
+	patients := []*athenahealth.Patient{}
+
+	for _, patient := range s.patients {
+		if len(r.Form.Get("firstname")) > 0 && !strings.EqualFold(patient.FirstName, r.Form.Get("firstname")) {
+			continue
+		}
+
+		if len(r.Form.Get("lastname")) > 0 && !strings.EqualFold(patient.LastName, r.Form.Get("lastname")) {
+			continue
+		}
+
+		if !matchParam(r, "departmentid", patient.DepartmentID) || !matchParam(r, "status", patient.Status) {
+			continue
+		}
+
+		patients = append(patients, patient)
+	}
+
+	patients, pagination := paginate(r, patients)
+
+	writeJSON(w, http.StatusOK, struct {
+		Patients []*athenahealth.Patient `json:"patients"`
+		athenahealth.PaginationResponse
+	}{patients, pagination})
+}
+
+func (s *Server) getPatient(w http.ResponseWriter, r *http.Request) {
+	patient := s.patient(r.PathValue("patientid"))
+	if patient == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []*athenahealth.Patient{patient})
+}
+
+func (s *Server) createPatient(w http.ResponseWriter, r *http.Request) {
+	var missing []string
+	for _, field := range []string{"departmentid", "dob", "firstname", "lastname"} {
+		if len(r.Form.Get(field)) == 0 {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error":         "Additional fields are required.",
+			"missingfields": missing,
+		})
+		return
+	}
+
+	if s.department(r.Form.Get("departmentid")) == nil {
+		writeError(w, http.StatusBadRequest, "Invalid department ID.")
+		return
+	}
+
+	bypass, _ := strconv.ParseBool(r.Form.Get("bypasspatientmatching"))
+	if !bypass {
+		for _, patient := range s.patients {
+			if strings.EqualFold(patient.FirstName, r.Form.Get("firstname")) &&
+				strings.EqualFold(patient.LastName, r.Form.Get("lastname")) &&
+				patient.DOB == r.Form.Get("dob") {
+				writeJSON(w, http.StatusOK, []map[string]string{{
+					"errormessage": "A duplicate patient already exists.",
+					"patientid":    patient.PatientID,
+				}})
+				return
+			}
+		}
+	}
+
+	patient := &athenahealth.Patient{
+		DepartmentID:        r.Form.Get("departmentid"),
+		PrimaryDepartmentID: r.Form.Get("departmentid"),
+		RegistrationDate:    s.now().Format(dateFormat),
+	}
+
+	updatePatientFields(patient, r)
+
+	id := s.addPatient(patient)
+
+	writeJSON(w, http.StatusOK, []map[string]string{{
+		"patientid": id,
+	}})
+}
+
+func (s *Server) updatePatient(w http.ResponseWriter, r *http.Request) {
+	patient := s.patient(r.PathValue("patientid"))
+	if patient == nil {
+		writeError(w, http.StatusNotFound, "The patient is not available.")
+		return
+	}
+
+	updatePatientFields(patient, r)
+	s.changedPatients.publish(patient)
+
+	writeJSON(w, http.StatusOK, []map[string]string{{
+		"patientid": patient.PatientID,
+	}})
+}
+
+// updatePatientFields sets the patient fields present in r's form.
+func updatePatientFields(patient *athenahealth.Patient, r *http.Request) {
+	fields := map[string]*string{
+		"address1":            &patient.Address1,
+		"address2":            &patient.Address2,
+		"city":                &patient.City,
+		"departmentid":        &patient.DepartmentID,
+		"dob":                 &patient.DOB,
+		"email":               &patient.Email,
+		"firstname":           &patient.FirstName,
+		"homephone":           &patient.HomePhone,
+		"lastname":            &patient.LastName,
+		"middlename":          &patient.MiddleName,
+		"mobilephone":         &patient.MobilePhone,
+		"notes":               &patient.Notes,
+		"preferredname":       &patient.PreferredName,
+		"primarydepartmentid": &patient.PrimaryDepartmentID,
+		"sex":                 &patient.Sex,
+		"ssn":                 &patient.SSN,
+		"state":               &patient.State,
+		"zip":                 &patient.Zip,
+	}
+
+	for name, field := range fields {
+		if values, ok := r.Form[name]; ok {
+			*field = values[0]
+		}
+	}
+
+	switch r.Form.Get("status") {
+	case "a", "active":
+		patient.Status = "active"
+	case "i", "inactive":
+		patient.Status = "inactive"
+	case "p", "prospective":
+		patient.Status = "prospective"
+	}
+}
+
+func (s *Server) listChangedPatients(w http.ResponseWriter, r *http.Request) {
+	patients := s.changedPatients.list(r, s.now(), s.location, func(patient *athenahealth.Patient) bool {
+		return matchParam(r, "patientid", patient.PatientID) && matchParam(r, "departmentid", patient.DepartmentID)
+	})
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"patients":   patients,
+		"totalcount": len(patients),
+	})
+}
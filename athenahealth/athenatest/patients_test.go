@@ -0,0 +1,118 @@
+package athenatest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/athenatime"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCreatePatientOptions(departmentID string) *athenahealth.CreatePatientOptions {
+	return &athenahealth.CreatePatientOptions{
+		DepartmentID: departmentID,
+		DOB:          time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		FirstName:    "John",
+		LastName:     "Doe",
+	}
+}
+
+func TestServer_CreatePatient(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	departmentID := srv.AddDepartment(&athenahealth.Department{})
+	client := srv.NewClient()
+
+	patientID, err := client.CreatePatient(ctx, testCreatePatientOptions(departmentID))
+	assert.NoError(err)
+
+	patient, err := client.GetPatient(ctx, patientID, nil)
+	assert.NoError(err)
+	assert.Equal("John", patient.FirstName)
+	assert.Equal("01/02/1990", patient.DOB)
+	assert.Equal(departmentID, patient.DepartmentID)
+
+	_, err = client.CreatePatient(ctx, testCreatePatientOptions(departmentID))
+	assert.ErrorIs(err, athenahealth.ErrConflict)
+
+	opts := testCreatePatientOptions(departmentID)
+	opts.BypassPatientMatching = true
+
+	_, err = client.CreatePatient(ctx, opts)
+	assert.NoError(err)
+
+	_, err = client.CreatePatient(ctx, &athenahealth.CreatePatientOptions{DepartmentID: departmentID})
+	assert.ErrorIs(err, athenahealth.ErrValidation)
+}
+
+func TestServer_UpdatePatient(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	patientID := srv.AddPatient(&athenahealth.Patient{FirstName: "John", LastName: "Doe"})
+	client := srv.NewClient()
+
+	email := "john@example.com"
+	res, err := client.UpdatePatient(ctx, patientID, &athenahealth.UpdatePatientOptions{
+		Email:  &email,
+		Status: &athenahealth.UpdatePatientStatusInactiveOption,
+	})
+	assert.NoError(err)
+	assert.Equal(patientID, res.PatientID)
+
+	patient := srv.Patient(patientID)
+	assert.Equal(email, patient.Email)
+	assert.Equal("inactive", patient.Status)
+	assert.Equal("John", patient.FirstName)
+
+	list, err := client.ListPatients(ctx, &athenahealth.ListPatientsOptions{Status: "inactive"})
+	assert.NoError(err)
+	assert.Len(list.Patients, 1)
+
+	_, err = client.UpdatePatient(ctx, "404", &athenahealth.UpdatePatientOptions{Email: &email})
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
+
+func TestServer_ListChangedPatients(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	patientID := srv.AddPatient(&athenahealth.Patient{FirstName: "John"})
+	client := srv.NewClient()
+
+	changed, err := client.ListChangedPatients(ctx, &athenahealth.ListChangedPatientOptions{LeaveUnprocessed: true})
+	assert.NoError(err)
+	assert.Len(changed, 1)
+
+	start := time.Now().Add(-time.Second)
+
+	changed, err = client.ListChangedPatients(ctx, nil)
+	assert.NoError(err)
+	assert.Len(changed, 1)
+	assert.Equal(patientID, changed[0].PatientID)
+
+	changed, err = client.ListChangedPatients(ctx, nil)
+	assert.NoError(err)
+	assert.Empty(changed)
+
+	// Processed records can be fetched again by the time they were processed, which athenahealth
+	// expects in Eastern time.
+	changed, err = client.ListChangedPatients(ctx, &athenahealth.ListChangedPatientOptions{
+		ShowProcessedStartDatetime: start.In(athenatime.Location()),
+		ShowProcessedEndDatetime:   time.Now().In(athenatime.Location()),
+	})
+	assert.NoError(err)
+	assert.Len(changed, 1)
+}
@@ -0,0 +1,65 @@
+package athenatest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// AddProvider adds a provider to the server and publishes it to the changed providers feed. If
+// provider.ProviderID is zero, an ID is assigned. The provider's ID is returned.
+func (s *Server) AddProvider(provider *athenahealth.Provider) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	provider = clone(provider)
+	if provider.ProviderID == 0 {
+		provider.ProviderID, _ = strconv.Atoi(s.id())
+	}
+
+	s.providers = append(s.providers, provider)
+	s.changedProviders.publish(provider)
+
+	return provider.ProviderID
+}
+
+func (s *Server) provider(id string) *athenahealth.Provider {
+	for _, provider := range s.providers {
+		if strconv.Itoa(provider.ProviderID) == id {
+			return provider
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listProviders(w http.ResponseWriter, r *http.Request) {
+	providers, pagination := paginate(r, s.providers)
+
+	writeJSON(w, http.StatusOK, struct {
+		Providers []*athenahealth.Provider `json:"providers"`
+		athenahealth.PaginationResponse
+	}{providers, pagination})
+}
+
+func (s *Server) getProvider(w http.ResponseWriter, r *http.Request) {
+	provider := s.provider(r.PathValue("providerid"))
+	if provider == nil {
+		writeError(w, http.StatusNotFound, "The provider is not available.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []*athenahealth.Provider{provider})
+}
+
+func (s *Server) listChangedProviders(w http.ResponseWriter, r *http.Request) {
+	providers := s.changedProviders.list(r, s.now(), s.location, func(*athenahealth.Provider) bool {
+		return true
+	})
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"providers":  providers,
+		"totalcount": len(providers),
+	})
+}
@@ -0,0 +1,306 @@
+// Package athenatest provides an in-memory fake of the athenahealth API for integration tests.
+//
+// A Server keeps departments, providers, patients, appointment slots, documents and insurance
+// packages in memory and serves them with the same paths, pagination and response envelopes as
+// athenahealth, so flows such as finding an open slot, booking it and checking the patient in can
+// be run against an *athenahealth.HTTPClient without network access:
+//
+//	srv := athenatest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddDepartment(&athenahealth.Department{DepartmentID: "1", Name: "Main"})
+//	slotID := srv.AddOpenSlot(&athenahealth.OpenAppointmentSlot{DepartmentID: 1, ProviderID: 1, Date: "01/02/2026", StartTime: "09:00"})
+//
+//	client := srv.NewClient()
+//	appt, err := client.BookAppointment(ctx, patientID, strconv.Itoa(slotID), nil)
+//
+// The server does not implement athenahealth's subscriptions: every change is published to the
+// changed feeds.
+package athenatest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/athenatime"
+)
+
+const (
+	// PracticeID is the practice ID served by a Server.
+	PracticeID = "195900"
+
+	// Token is the access token a Server accepts. Clients returned by NewClient use it.
+	Token = "athenatest-token"
+
+	// defaultLimit is the page size used when a request does not set limit.
+	defaultLimit = 1500
+
+	dateFormat     = "01/02/2006"
+	datetimeFormat = "01/02/2006 15:04:05"
+)
+
+// Server is a stateful fake athenahealth API server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:1234.
+	URL string
+
+	server *httptest.Server
+
+	departments []*athenahealth.Department
+	providers   []*athenahealth.Provider
+	patients    []*athenahealth.Patient
+	// appointments holds open slots (status o) as well as booked appointments.
+	appointments []*athenahealth.BookedAppointment
+	documents    map[string][]*Document
+	insurances   map[string][]*athenahealth.InsurancePackage
+
+	changedPatients     *feed[*athenahealth.Patient]
+	changedAppointments *feed[*athenahealth.BookedAppointment]
+	changedProviders    *feed[*athenahealth.Provider]
+
+	nextID   int
+	location *time.Location
+
+	lock sync.Mutex
+}
+
+// NewServer starts a Server. Callers should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		documents:  make(map[string][]*Document),
+		insurances: make(map[string][]*athenahealth.InsurancePackage),
+
+		changedPatients:     &feed[*athenahealth.Patient]{},
+		changedAppointments: &feed[*athenahealth.BookedAppointment]{},
+		changedProviders:    &feed[*athenahealth.Provider]{},
+
+		nextID:   1000,
+		location: athenatime.Location(),
+	}
+
+	s.server = httptest.NewServer(s.handler())
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// NewClient returns an *athenahealth.HTTPClient that sends requests to the server. Retries are
+// disabled so failures surface immediately.
+func (s *Server) NewClient() *athenahealth.HTTPClient {
+	retryPolicy := athenahealth.NewRetryPolicy()
+	retryPolicy.MaxAttempts = 1
+
+	return athenahealth.NewHTTPClient(s.server.Client(), PracticeID, "athenatest", "athenatest").
+		WithBaseURL(s.URL + "/v1/").
+		WithTokenProvider(&tokenProvider{}).
+		WithRetryPolicy(retryPolicy)
+}
+
+type tokenProvider struct{}
+
+func (t *tokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	return Token, time.Now().Add(time.Hour), nil
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	route := func(method, path string, h http.HandlerFunc) {
+		mux.HandleFunc(fmt.Sprintf("%s /v1/{practiceid}%s", method, path), func(w http.ResponseWriter, r *http.Request) {
+			if r.PathValue("practiceid") != PracticeID {
+				writeError(w, http.StatusNotFound, "Invalid practice ID.")
+				return
+			}
+
+			h(w, r)
+		})
+	}
+
+	route(http.MethodGet, "/departments", s.listDepartments)
+	route(http.MethodGet, "/departments/{departmentid}", s.getDepartment)
+
+	route(http.MethodGet, "/providers", s.listProviders)
+	route(http.MethodGet, "/providers/changed", s.listChangedProviders)
+	route(http.MethodGet, "/providers/{providerid}", s.getProvider)
+
+	route(http.MethodGet, "/patients", s.listPatients)
+	route(http.MethodPost, "/patients", s.createPatient)
+	route(http.MethodGet, "/patients/changed", s.listChangedPatients)
+	route(http.MethodGet, "/patients/{patientid}", s.getPatient)
+	route(http.MethodPut, "/patients/{patientid}", s.updatePatient)
+
+	route(http.MethodGet, "/patients/{patientid}/documents/admin", s.listAdminDocuments)
+	route(http.MethodPost, "/patients/{patientid}/documents", s.addDocument)
+
+	route(http.MethodGet, "/patients/{patientid}/insurances", s.listInsurances)
+	route(http.MethodPost, "/patients/{patientid}/insurances", s.createInsurance)
+	route(http.MethodPut, "/patients/{patientid}/insurances/{insuranceid}", s.updateInsurance)
+	route(http.MethodDelete, "/patients/{patientid}/insurances/{insuranceid}", s.deleteInsurance)
+
+	route(http.MethodGet, "/appointments/open", s.listOpenSlots)
+	route(http.MethodPost, "/appointments/open", s.createOpenSlots)
+	route(http.MethodGet, "/appointments/booked", s.listBookedAppointments)
+	route(http.MethodGet, "/appointments/changed", s.listChangedAppointments)
+	route(http.MethodGet, "/appointments/{appointmentid}", s.getAppointment)
+	route(http.MethodPut, "/appointments/{appointmentid}", s.bookAppointment)
+	route(http.MethodPut, "/appointments/{appointmentid}/reschedule", s.rescheduleAppointment)
+	route(http.MethodPut, "/appointments/{appointmentid}/cancel", s.cancelAppointment)
+	route(http.MethodPost, "/appointments/{appointmentid}/startcheckin", s.startCheckIn)
+	route(http.MethodPost, "/appointments/{appointmentid}/cancelcheckin", s.cancelCheckIn)
+	route(http.MethodPost, "/appointments/{appointmentid}/checkin", s.checkIn)
+	route(http.MethodPost, "/appointments/{appointmentid}/checkout", s.checkOut)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		err := parseForm(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// parseForm parses the query and form body of r into r.Form. Unlike http.Request.ParseForm, it
+// also parses the body of DELETE requests, which athenahealth accepts.
+func parseForm(r *http.Request) error {
+	if r.Method == http.MethodDelete {
+		r.Method = http.MethodPost
+		defer func() { r.Method = http.MethodDelete }()
+	}
+
+	return r.ParseForm()
+}
+
+func (s *Server) id() string {
+	s.nextID++
+
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().In(s.location)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	//nolint
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an athenahealth error response, which APIError decodes.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
+
+// writeEnvelopeError writes the 200 response athenahealth uses for some failures.
+func writeEnvelopeError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":      false,
+		"errormessage": message,
+	})
+}
+
+func writeSuccess(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+	})
+}
+
+// paginate returns the page of items requested by r's limit and offset, along with the
+// previous/next links and total count athenahealth includes in list responses.
+func paginate[T any](r *http.Request, items []T) ([]T, athenahealth.PaginationResponse) {
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+
+	offset, err := strconv.Atoi(r.Form.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	pagination := athenahealth.PaginationResponse{
+		TotalCount: len(items),
+	}
+
+	link := func(offset int) string {
+		q := url.Values{}
+		for k, v := range r.Form {
+			q[k] = v
+		}
+
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+
+		return fmt.Sprintf("%s?%s", r.URL.Path, q.Encode())
+	}
+
+	if offset > 0 {
+		pagination.Previous = link(max(offset-limit, 0))
+	}
+
+	if offset+limit < len(items) {
+		pagination.Next = link(offset + limit)
+	}
+
+	if offset >= len(items) {
+		return []T{}, pagination
+	}
+
+	return items[offset:min(offset+limit, len(items))], pagination
+}
+
+func parseDate(s string) (time.Time, bool) {
+	t, err := time.Parse(dateFormat, s)
+
+	return t, err == nil
+}
+
+// inDateRange reports whether date (mm/dd/yyyy) is within the inclusive range given by the
+// startdate and enddate parameters of r.
+func inDateRange(r *http.Request, date string) bool {
+	d, ok := parseDate(date)
+	if !ok {
+		return false
+	}
+
+	if start, ok := parseDate(r.Form.Get("startdate")); ok && d.Before(start) {
+		return false
+	}
+
+	if end, ok := parseDate(r.Form.Get("enddate")); ok && d.After(end) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,188 @@
+package athenatest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_unauthorized(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/"+PracticeID+"/departments", nil)
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(err)
+	defer func() { _ = res.Body.Close() }()
+
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestServer_notFound(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+
+	_, err := srv.NewClient().GetDepartment(context.Background(), "1")
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+
+	_, err = srv.NewClient().Get(context.Background(), "/unknown", nil, nil)
+	assert.ErrorIs(err, athenahealth.ErrNotFound)
+}
+
+func TestServer_pagination(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := NewServer()
+	defer srv.Close()
+
+	for i := range 5 {
+		srv.AddDepartment(&athenahealth.Department{Name: strconv.Itoa(i)})
+	}
+
+	client := srv.NewClient()
+
+	res, err := client.ListDepartments(context.Background(), &athenahealth.ListDepartmentsOptions{
+		Pagination: &athenahealth.PaginationOptions{Limit: 2, Offset: 2},
+	})
+	assert.NoError(err)
+	assert.Len(res.Departments, 2)
+	assert.Equal("2", res.Departments[0].Name)
+	assert.Equal(4, res.Pagination.NextOffset)
+	assert.Equal(0, res.Pagination.PreviousOffset)
+	assert.Equal(5, res.Pagination.TotalCount)
+
+	var names []string
+	for department, err := range client.AllDepartments(context.Background(), &athenahealth.ListDepartmentsOptions{
+		Pagination: &athenahealth.PaginationOptions{Limit: 2},
+	}) {
+		assert.NoError(err)
+		names = append(names, department.Name)
+	}
+
+	assert.Equal([]string{"0", "1", "2", "3", "4"}, names)
+}
+
+func TestServer_schedulingFlow(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	departmentID := srv.AddDepartment(&athenahealth.Department{Name: "Main Street"})
+	providerID := srv.AddProvider(&athenahealth.Provider{FirstName: "Jane", LastName: "Smith"})
+
+	client := srv.NewClient()
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+
+	created, err := client.CreateAppointmentSlot(ctx, &athenahealth.CreateAppointmentSlotOptions{
+		AppointmentDate: tomorrow.Format("01/02/2006"),
+		AppointmentTime: []string{"09:00", "10:00"},
+		DepartmentID:    atoi(departmentID),
+		ProviderID:      providerID,
+	})
+	assert.NoError(err)
+	assert.Len(created.AppointmentIDs, 2)
+
+	patientID, err := client.CreatePatient(ctx, &athenahealth.CreatePatientOptions{
+		DepartmentID: departmentID,
+		DOB:          time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		FirstName:    "John",
+		LastName:     "Doe",
+		Email:        "john@example.com",
+	})
+	assert.NoError(err)
+
+	slots, err := client.ListOpenAppointmentSlots(ctx, atoi(departmentID), &athenahealth.ListOpenAppointmentSlotOptions{
+		ProviderIDs: []int{providerID},
+		StartDate:   tomorrow,
+		EndDate:     tomorrow,
+	})
+	assert.NoError(err)
+	if !assert.Len(slots.Appointments, 2) {
+		return
+	}
+
+	first := strconv.Itoa(slots.Appointments[0].AppointmentID)
+	second := slots.Appointments[1].AppointmentID
+
+	booked, err := client.BookAppointment(ctx, patientID, first, nil)
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusFuture, booked.AppointmentStatus)
+	assert.Equal(patientID, booked.PatientID)
+
+	// A booked slot can't be booked again.
+	_, err = client.BookAppointment(ctx, patientID, first, nil)
+	assert.ErrorIs(err, athenahealth.ErrConflict)
+
+	rescheduled, err := client.RescheduleAppointment(ctx, atoi(first), &athenahealth.RescheduleAppointmentOptions{
+		NewAppointmentID: second,
+		PatientID:        atoi(patientID),
+	})
+	assert.NoError(err)
+	assert.Equal(strconv.Itoa(second), rescheduled.AppointmentID)
+
+	original, err := client.GetAppointment(ctx, first)
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusCancelled, original.AppointmentStatus)
+	assert.Equal(strconv.Itoa(second), srv.Appointment(first).RescheduledAppointmentID)
+
+	slots, err = client.ListOpenAppointmentSlots(ctx, atoi(departmentID), nil)
+	assert.NoError(err)
+	assert.Empty(slots.Appointments)
+
+	apptID := strconv.Itoa(second)
+
+	// Check-out before check-in fails with a success:false envelope.
+	assert.Error(client.AppointmentCheckOut(ctx, apptID))
+
+	assert.NoError(client.AppointmentStartCheckIn(ctx, apptID))
+	assert.NoError(client.AppointmentCheckIn(ctx, apptID))
+	assert.NoError(client.AppointmentCheckOut(ctx, apptID))
+
+	appt, err := client.GetAppointment(ctx, apptID)
+	assert.NoError(err)
+	assert.Equal(athenahealth.AppointmentStatusCheckedOut, appt.AppointmentStatus)
+	assert.NotEmpty(appt.EncounterID)
+
+	booked2, err := client.ListBookedAppointments(ctx, &athenahealth.ListBookedAppointmentsOptions{
+		PatientID: patientID,
+		StartDate: tomorrow,
+		EndDate:   tomorrow,
+	})
+	assert.NoError(err)
+	assert.Len(booked2.BookedAppointments, 2)
+
+	changed, err := client.ListChangedAppointments(ctx, &athenahealth.ListChangedAppointmentsOptions{
+		PatientID: patientID,
+	})
+	assert.NoError(err)
+
+	var statuses []athenahealth.AppointmentStatus
+	for _, c := range changed {
+		statuses = append(statuses, c.AppointmentStatus)
+	}
+
+	assert.Equal([]athenahealth.AppointmentStatus{
+		athenahealth.AppointmentStatusFuture,    // booked
+		athenahealth.AppointmentStatusCancelled, // rescheduled from
+		athenahealth.AppointmentStatusFuture,    // rescheduled to
+		athenahealth.AppointmentStatusFuture,    // check-in started
+		athenahealth.AppointmentStatusCheckedIn,
+		athenahealth.AppointmentStatusCheckedOut,
+	}, statuses)
+
+	changed, err = client.ListChangedAppointments(ctx, nil)
+	assert.NoError(err)
+	assert.Empty(changed)
+}
@@ -0,0 +1,103 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that records redacted interactions. The responses returned to
+// the caller are not redacted.
+type Recorder struct {
+	path      string
+	transport http.RoundTripper
+	redactor  *Redactor
+
+	cassette *Cassette
+	lock     sync.Mutex
+}
+
+// NewRecorder returns a Recorder that sends requests with transport, or http.DefaultTransport if
+// transport is nil, and saves the cassette to path.
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{
+		path:      path,
+		transport: transport,
+		redactor:  NewRedactor(),
+
+		cassette: &Cassette{},
+	}
+}
+
+// WithRedactor replaces the default Redactor.
+func (r *Recorder) WithRedactor(redactor *Redactor) *Recorder {
+	r.redactor = redactor
+
+	return r
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+
+	if req.Body != nil {
+		var err error
+
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = res.Body.Close()
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	interaction := &Interaction{
+		Request: &Request{
+			Method: req.Method,
+			URL:    r.redactor.redactURL(req.URL),
+			Header: r.redactor.redactHeader(req.Header),
+			Body:   r.redactor.redactBody(req.Header.Get("Content-Type"), reqBody),
+		},
+		Response: &Response{
+			StatusCode: res.StatusCode,
+			Header:     r.redactor.redactHeader(res.Header),
+			Body:       r.redactor.redactBody(res.Header.Get("Content-Type"), resBody),
+		},
+	}
+
+	r.lock.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.lock.Unlock()
+
+	return res, nil
+}
+
+// Save writes the interactions recorded so far to the Recorder's path.
+func (r *Recorder) Save() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.cassette.Save(r.path)
+}
@@ -0,0 +1,100 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/athenatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenProvider struct{}
+
+func (t *tokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	return athenatest.Token, time.Now().Add(time.Hour), nil
+}
+
+func newTestClient(baseURL string, transport http.RoundTripper) *athenahealth.HTTPClient {
+	retryPolicy := athenahealth.NewRetryPolicy()
+	retryPolicy.MaxAttempts = 1
+
+	return athenahealth.NewHTTPClient(&http.Client{Transport: transport}, athenatest.PracticeID, "key", "secret").
+		WithBaseURL(baseURL + "/v1/").
+		WithTokenProvider(&tokenProvider{}).
+		WithRetryPolicy(retryPolicy)
+}
+
+// record records creating and fetching a patient against an athenatest.Server and returns the
+// cassette path and the patient ID.
+func record(t *testing.T) (string, string) {
+	t.Helper()
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	srv := athenatest.NewServer()
+	defer srv.Close()
+
+	srv.AddDepartment(&athenahealth.Department{DepartmentID: "1"})
+
+	path := filepath.Join(t.TempDir(), "cassettes", "patient.json")
+	recorder := NewRecorder(path, nil)
+	client := newTestClient(srv.URL, recorder)
+
+	patientID, err := client.CreatePatient(ctx, &athenahealth.CreatePatientOptions{
+		DepartmentID: "1",
+		DOB:          time.Date(1984, time.February, 3, 0, 0, 0, 0, time.UTC),
+		FirstName:    "Jane",
+		LastName:     "Doe",
+		MobilePhone:  "6175551234",
+		SSN:          "123-45-6789",
+	})
+	assert.NoError(err)
+
+	patient, err := client.GetPatient(ctx, patientID, nil)
+	assert.NoError(err)
+
+	// The caller gets the real response.
+	assert.Equal("Jane", patient.FirstName)
+
+	assert.NoError(recorder.Save())
+
+	return path, patientID
+}
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	path, _ := record(t)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(err)
+
+	for _, phi := range []string{"Jane", "Doe", "1984", "6175551234", "123-45-6789", athenatest.Token} {
+		assert.NotContains(string(contents), phi)
+	}
+
+	c, err := Load(path)
+	assert.NoError(err)
+	assert.Len(c.Interactions, 2)
+
+	assert.Equal(http.MethodPost, c.Interactions[0].Request.Method)
+	assert.Equal("/v1/"+athenatest.PracticeID+"/patients", c.Interactions[0].Request.URL)
+	assert.Equal("REDACTED", c.Interactions[0].Request.Header.Get("Authorization"))
+	assert.Equal(http.StatusOK, c.Interactions[0].Response.StatusCode)
+
+	assert.Equal(http.MethodGet, c.Interactions[1].Request.Method)
+}
+
+func TestNewRecorder_pathRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewRecorder("", nil)
+	})
+}
@@ -0,0 +1,223 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Redacted replaces redacted header values and free text.
+	Redacted = "REDACTED"
+
+	redactedName  = "REDACTED"
+	redactedDOB   = "01/01/1970"
+	redactedSSN   = "000-00-0000"
+	redactedPhone = "5555550100"
+	redactedEmail = "redacted@example.com"
+
+	// minBase64Length is the shortest string value treated as an attachment when its key is not
+	// known. Shorter values are too likely to be IDs or codes.
+	minBase64Length = 256
+)
+
+// redactedBase64 is valid base64 so fixtures still decode.
+var redactedBase64 = base64.StdEncoding.EncodeToString([]byte(Redacted))
+
+var (
+	ssnPattern   = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	phonePattern = regexp.MustCompile(`\(?\b\d{3}\)?[-. ]\d{3}[-. ]\d{4}\b`)
+)
+
+// Redactor removes PHI from recorded requests and responses. Values are replaced by key: JSON
+// object keys, form fields and query parameters are matched, case-insensitively, against key
+// suffixes (so "lastname" matches "guarantorlastname"). Replacements keep the format of the
+// original value, e.g. dates of birth become 01/01/1970, so fixtures still decode. SSNs and phone
+// numbers are also masked in free text, and long base64 strings are replaced whatever their key.
+type Redactor struct {
+	rules   []redactRule
+	headers map[string]bool
+}
+
+type redactRule struct {
+	suffixes    []string
+	replacement string
+}
+
+// NewRedactor returns a Redactor that redacts names, dates of birth, SSNs, phone numbers, email
+// addresses, street addresses, base64 attachments, access tokens and the Authorization header.
+func NewRedactor() *Redactor {
+	r := &Redactor{
+		headers: make(map[string]bool),
+	}
+
+	return r.
+		WithKeys(redactedName, "firstname", "lastname", "middlename", "preferredname", "contactname", "policyholder", "nextkinname", "guardianname").
+		WithKeys(redactedDOB, "dob", "birthdate", "deceaseddate").
+		WithKeys(redactedSSN, "ssn").
+		WithKeys(redactedPhone, "phone", "fax").
+		WithKeys(redactedEmail, "email").
+		WithKeys(Redacted, "address1", "address2", "access_token", "client_secret", "client_assertion").
+		WithKeys(redactedBase64, "attachmentcontents", "image", "base64").
+		WithHeaders("Authorization", "Cookie", "Set-Cookie")
+}
+
+// WithKeys redacts the values of keys ending with any of suffixes with replacement.
+func (r *Redactor) WithKeys(replacement string, suffixes ...string) *Redactor {
+	lower := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		lower[i] = strings.ToLower(suffix)
+	}
+
+	r.rules = append(r.rules, redactRule{
+		suffixes:    lower,
+		replacement: replacement,
+	})
+
+	return r
+}
+
+// WithHeaders redacts the values of the given headers.
+func (r *Redactor) WithHeaders(headers ...string) *Redactor {
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return r
+}
+
+func (r *Redactor) redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	for key := range redacted {
+		if r.headers[key] {
+			redacted[key] = []string{Redacted}
+		}
+	}
+
+	return redacted
+}
+
+func (r *Redactor) redactURL(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.Path
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return u.Path + "?" + Redacted
+	}
+
+	return u.Path + "?" + r.redactValues(query).Encode()
+}
+
+func (r *Redactor) redactValues(values url.Values) url.Values {
+	redacted := make(url.Values, len(values))
+
+	for key, vals := range values {
+		for _, val := range vals {
+			redacted.Add(key, r.redactValue(key, val))
+		}
+	}
+
+	return redacted
+}
+
+// redactBody redacts JSON and form bodies. Other bodies only have SSNs and phone numbers masked.
+func (r *Redactor) redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	trimmed := bytes.TrimSpace(body)
+
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+
+		var v any
+
+		if dec.Decode(&v) == nil {
+			b, err := json.Marshal(r.redactJSON("", v))
+			if err == nil {
+				return string(b)
+			}
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			return r.redactValues(values).Encode()
+		}
+	}
+
+	return r.redactText(string(body))
+}
+
+func (r *Redactor) redactJSON(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = r.redactJSON(k, val)
+		}
+
+		return v
+
+	case []any:
+		for i, val := range v {
+			v[i] = r.redactJSON(key, val)
+		}
+
+		return v
+
+	case string:
+		return r.redactValue(key, v)
+	}
+
+	return v
+}
+
+func (r *Redactor) redactValue(key, value string) string {
+	if len(value) == 0 {
+		return value
+	}
+
+	key = strings.ToLower(key)
+
+	for _, rule := range r.rules {
+		for _, suffix := range rule.suffixes {
+			if strings.HasSuffix(key, suffix) {
+				return rule.replacement
+			}
+		}
+	}
+
+	if isBase64(value) {
+		return redactedBase64
+	}
+
+	return r.redactText(value)
+}
+
+func (r *Redactor) redactText(s string) string {
+	s = ssnPattern.ReplaceAllString(s, redactedSSN)
+
+	return phonePattern.ReplaceAllString(s, redactedPhone)
+}
+
+func isBase64(s string) bool {
+	if len(s) < minBase64Length || strings.ContainsAny(s, " \n") {
+		return false
+	}
+
+	_, err := base64.StdEncoding.DecodeString(s)
+
+	return err == nil
+}
@@ -0,0 +1,109 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_redactBody_json(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor()
+
+	body := `[{"patientid":"1","firstname":"Jane","guarantorlastname":"Doe","dob":"02/03/1984","ssn":"123-45-6789",` +
+		`"mobilephone":"6175551234","email":"jane@example.com","notes":"call 617-555-1234","balance":12.50,` +
+		`"contacts":[{"contactname":"John Doe"}]}]`
+
+	redacted := r.redactBody("application/json", []byte(body))
+
+	assert.JSONEq(`[{"patientid":"1","firstname":"REDACTED","guarantorlastname":"REDACTED","dob":"01/01/1970","ssn":"000-00-0000",`+
+		`"mobilephone":"5555550100","email":"redacted@example.com","notes":"call 5555550100","balance":12.50,`+
+		`"contacts":[{"contactname":"REDACTED"}]}]`, redacted)
+}
+
+func TestRedactor_redactBody_base64(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor()
+
+	attachment := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("scanned card ", 50)))
+
+	redacted := r.redactBody("application/json", []byte(`{"image":"abc","contents":"`+attachment+`","documentid":"1"}`))
+	assert.JSONEq(`{"image":"UkVEQUNURUQ=","contents":"UkVEQUNURUQ=","documentid":"1"}`, redacted)
+}
+
+func TestRedactor_redactBody_form(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor()
+
+	redacted := r.redactBody("application/x-www-form-urlencoded", []byte("departmentid=1&firstname=Jane&dob=02%2F03%2F1984"))
+
+	values, err := url.ParseQuery(redacted)
+	assert.NoError(err)
+	assert.Equal("1", values.Get("departmentid"))
+	assert.Equal("REDACTED", values.Get("firstname"))
+	assert.Equal("01/01/1970", values.Get("dob"))
+}
+
+func TestRedactor_redactBody_text(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor()
+
+	assert.Equal("ssn 000-00-0000, phone 5555550100", r.redactBody("text/plain", []byte("ssn 123-45-6789, phone (617) 555-1234")))
+	assert.Equal("", r.redactBody("", nil))
+}
+
+func TestRedactor_redactURL(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor()
+
+	u, _ := url.Parse("https://api.preview.platform.athenahealth.com/v1/195900/patients?lastname=Doe&departmentid=1")
+	assert.Equal("/v1/195900/patients?departmentid=1&lastname=REDACTED", r.redactURL(u))
+
+	u, _ = url.Parse("https://api.preview.platform.athenahealth.com/v1/195900/patients/1")
+	assert.Equal("/v1/195900/patients/1", r.redactURL(u))
+}
+
+func TestRedactor_redactHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor().WithHeaders("X-Api-Key")
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-Api-Key", "secret")
+	header.Set("Content-Type", "application/json")
+
+	redacted := r.redactHeader(header)
+	assert.Equal("REDACTED", redacted.Get("Authorization"))
+	assert.Equal("REDACTED", redacted.Get("X-Api-Key"))
+	assert.Equal("application/json", redacted.Get("Content-Type"))
+
+	assert.Equal("Bearer secret", header.Get("Authorization"))
+}
+
+func TestRedactor_WithKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRedactor().WithKeys("X", "MRN")
+
+	assert.JSONEq(`{"patientmrn":"X"}`, r.redactBody("application/json", []byte(`{"patientmrn":"12345"}`)))
+}
+
+func TestRedactor_WithKeys_doesNotModifySuffixes(t *testing.T) {
+	assert := assert.New(t)
+
+	keys := []string{"MRN", "Pronouns"}
+	r := NewRedactor().WithKeys("X", keys...)
+
+	assert.Equal([]string{"MRN", "Pronouns"}, keys)
+	assert.JSONEq(`{"patientpronouns":"X"}`, r.redactBody("application/json", []byte(`{"patientpronouns":"they"}`)))
+}
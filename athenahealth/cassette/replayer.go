@@ -0,0 +1,124 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrInteractionNotFound is returned by Replayer when no unused interaction matches a request.
+var ErrInteractionNotFound = errors.New("no matching interaction in cassette")
+
+// Replayer is an http.RoundTripper that serves the responses of a cassette. A request matches an
+// interaction with the same method, URL path and query string, and body, after the request is
+// redacted the same way it was when it was recorded. Each interaction is served once, in the order
+// it was recorded.
+type Replayer struct {
+	cassette *Cassette
+	redactor *Redactor
+	used     []bool
+
+	lock sync.Mutex
+}
+
+// NewReplayer returns a Replayer for the cassette at path.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCassetteReplayer(c), nil
+}
+
+// NewCassetteReplayer returns a Replayer for c.
+func NewCassetteReplayer(c *Cassette) *Replayer {
+	if c == nil {
+		panic("cassette is nil")
+	}
+
+	return &Replayer{
+		cassette: c,
+		redactor: NewRedactor(),
+		used:     make([]bool, len(c.Interactions)),
+	}
+}
+
+// WithRedactor replaces the default Redactor. It must match the Redactor used to record the
+// cassette.
+func (r *Replayer) WithRedactor(redactor *Redactor) *Replayer {
+	r.redactor = redactor
+
+	return r
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+
+	if req.Body != nil {
+		var err error
+
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	url := r.redactor.redactURL(req.URL)
+	body := r.redactor.redactBody(req.Header.Get("Content-Type"), reqBody)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+
+		if interaction.Request.Method != req.Method || interaction.Request.URL != url || interaction.Request.Body != body {
+			continue
+		}
+
+		r.used[i] = true
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, url)
+}
+
+// Unused returns the interactions that have not been served, e.g. to check that a test made
+// every recorded request.
+func (r *Replayer) Unused() []*Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var unused []*Interaction
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,65 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayer(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	path, patientID := record(t)
+
+	replayer, err := NewReplayer(path)
+	assert.NoError(err)
+
+	// No server is running, so every response comes from the cassette.
+	client := newTestClient("http://athenahealth.invalid", replayer)
+
+	assert.Len(replayer.Unused(), 2)
+
+	// Request values are redacted before matching, so different PHI still matches.
+	id, err := client.CreatePatient(ctx, &athenahealth.CreatePatientOptions{
+		DepartmentID: "1",
+		DOB:          time.Date(1990, time.May, 6, 0, 0, 0, 0, time.UTC),
+		FirstName:    "Alex",
+		LastName:     "Roe",
+		MobilePhone:  "2125559876",
+		SSN:          "987-65-4321",
+	})
+	assert.NoError(err)
+	assert.Equal(patientID, id)
+
+	patient, err := client.GetPatient(ctx, patientID, nil)
+	assert.NoError(err)
+	assert.Equal(patientID, patient.PatientID)
+	assert.Equal("REDACTED", patient.FirstName)
+	assert.Equal("01/01/1970", patient.DOB)
+
+	assert.Empty(replayer.Unused())
+
+	// Each interaction is only served once.
+	_, err = client.GetPatient(ctx, patientID, nil)
+	assert.True(errors.Is(err, ErrInteractionNotFound))
+}
+
+func TestNewReplayer_missingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewReplayer("testdata/missing.json")
+	assert.Error(err)
+}
+
+func TestNewCassetteReplayer_nil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewCassetteReplayer(nil)
+	})
+}
@@ -0,0 +1,336 @@
+// Package changefeed polls athenahealth "changed" feeds (e.g. ListChangedPatients) and
+// dispatches the changed records to handlers with at-least-once delivery.
+//
+// Each poll first peeks at unprocessed records using LeaveUnprocessed, dispatches them, and only
+// then acknowledges them by fetching the feed again without LeaveUnprocessed, which marks them
+// processed in athenahealth. The start of the acknowledgement is saved to a CheckpointStore before
+// it is sent, so records that arrive between the peek and the acknowledgement, which are marked
+// processed without having been handled, can be replayed with the feed's
+// ShowProcessed{Start,End}Datetime options if handling them fails or the process dies.
+package changefeed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/athenatime"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultMinInterval  = 5 * time.Second
+	defaultMaxInterval  = 2 * time.Minute
+	defaultReplayMargin = 5 * time.Minute
+)
+
+// Query is passed to a Feed's fetch function for every call to athenahealth.
+type Query struct {
+	// LeaveUnprocessed fetches unprocessed records without marking them processed.
+	LeaveUnprocessed bool
+
+	// ProcessedStart and ProcessedEnd, when set, fetch records that were already marked processed
+	// within the window instead of unprocessed records.
+	ProcessedStart time.Time
+	ProcessedEnd   time.Time
+}
+
+// FetchFunc fetches the records of a feed for a Query.
+type FetchFunc[T any] func(ctx context.Context, q Query) ([]T, error)
+
+// Feed is an athenahealth changed feed. Name identifies the feed in the CheckpointStore and must
+// be unique per store.
+type Feed[T any] struct {
+	Name  string
+	Fetch FetchFunc[T]
+}
+
+// Handler handles a changed record. Returning an error stops the poll; the record (and any not yet
+// handled) will be delivered again.
+type Handler[T any] func(ctx context.Context, record T) error
+
+// Poller polls a Feed and dispatches its records to a Handler.
+type Poller[T any] struct {
+	feed        Feed[T]
+	handler     Handler[T]
+	checkpoints CheckpointStore
+
+	minInterval  time.Duration
+	maxInterval  time.Duration
+	replayMargin time.Duration
+	location     *time.Location
+	logger       *zerolog.Logger
+}
+
+func NewPoller[T any](feed Feed[T], handler Handler[T], checkpoints CheckpointStore) *Poller[T] {
+	if feed.Fetch == nil {
+		panic("feed fetch is nil")
+	}
+
+	if handler == nil {
+		panic("handler is nil")
+	}
+
+	if checkpoints == nil {
+		panic("checkpoints is nil")
+	}
+
+	noplogger := zerolog.Nop()
+
+	return &Poller[T]{
+		feed:        feed,
+		handler:     handler,
+		checkpoints: checkpoints,
+
+		minInterval:  defaultMinInterval,
+		maxInterval:  defaultMaxInterval,
+		replayMargin: defaultReplayMargin,
+		location:     athenatime.Location(),
+		logger:       &noplogger,
+	}
+}
+
+// WithInterval sets the delay between polls. The delay starts at minInterval, doubles after every
+// poll that returns no records or fails, up to maxInterval, and resets to minInterval once records
+// are returned.
+func (p *Poller[T]) WithInterval(minInterval, maxInterval time.Duration) *Poller[T] {
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	p.minInterval = minInterval
+	p.maxInterval = maxInterval
+
+	return p
+}
+
+// WithReplayMargin widens replayed windows on both sides to account for clock skew between this
+// process and athenahealth.
+func (p *Poller[T]) WithReplayMargin(margin time.Duration) *Poller[T] {
+	p.replayMargin = margin
+
+	return p
+}
+
+// WithLocation sets the time zone athenahealth uses for processed datetimes. It defaults to
+// America/New_York.
+func (p *Poller[T]) WithLocation(location *time.Location) *Poller[T] {
+	p.location = location
+
+	return p
+}
+
+func (p *Poller[T]) WithLogger(logger *zerolog.Logger) *Poller[T] {
+	p.logger = logger
+
+	return p
+}
+
+// Run polls the feed until ctx is canceled. Poll errors are logged and retried with backoff.
+func (p *Poller[T]) Run(ctx context.Context) error {
+	interval := p.minInterval
+
+	for {
+		n, err := p.Poll(ctx)
+		if err != nil {
+			p.logger.Error().
+				Str("feed", p.feed.Name).
+				Err(err).
+				Msg("athenahealth changed feed poll failed")
+		}
+
+		if err != nil || n == 0 {
+			interval = min(interval*2, p.maxInterval)
+		} else {
+			interval = p.minInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Poll runs a single poll of the feed and returns the number of records dispatched.
+func (p *Poller[T]) Poll(ctx context.Context) (int, error) {
+	cp, err := p.checkpoints.Load(ctx, p.feed.Name)
+	if err != nil {
+		return 0, fmt.Errorf("loading checkpoint: %w", err)
+	}
+
+	if cp == nil {
+		cp = &Checkpoint{}
+	}
+
+	dispatched := 0
+
+	if cp.pending() {
+		n, err := p.replay(ctx, cp)
+		dispatched += n
+		if err != nil {
+			return dispatched, err
+		}
+	}
+
+	peeked, err := p.feed.Fetch(ctx, Query{LeaveUnprocessed: true})
+	if err != nil {
+		return dispatched, fmt.Errorf("fetching unprocessed records: %w", err)
+	}
+
+	if len(peeked) == 0 {
+		return dispatched, nil
+	}
+
+	n, err := p.dispatch(ctx, peeked)
+	dispatched += n
+	if err != nil {
+		return dispatched, err
+	}
+
+	// Records that arrive before the acknowledgement are marked processed by it without having
+	// been handled, so the start of the acknowledgement window is saved first. If the poll fails
+	// before the window is committed, the next poll replays it.
+	cp.PendingStart = time.Now()
+	cp.PendingEnd = time.Time{}
+
+	err = p.checkpoints.Save(ctx, p.feed.Name, cp)
+	if err != nil {
+		return dispatched, fmt.Errorf("saving checkpoint: %w", err)
+	}
+
+	acked, err := p.feed.Fetch(ctx, Query{})
+	if err != nil {
+		return dispatched, fmt.Errorf("acknowledging records: %w", err)
+	}
+
+	end := time.Now()
+
+	extra, err := difference(acked, peeked)
+	if err != nil {
+		return dispatched, err
+	}
+
+	if len(extra) > 0 {
+		// Close the window so a replay does not have to extend to the time it runs.
+		cp.PendingEnd = end
+
+		err = p.checkpoints.Save(ctx, p.feed.Name, cp)
+		if err != nil {
+			return dispatched, fmt.Errorf("saving checkpoint: %w", err)
+		}
+
+		n, err := p.dispatch(ctx, extra)
+		dispatched += n
+		if err != nil {
+			return dispatched, err
+		}
+	}
+
+	err = p.commit(ctx, cp, end)
+	if err != nil {
+		return dispatched, err
+	}
+
+	return dispatched, nil
+}
+
+// replay dispatches the records that were marked processed in the checkpoint's pending window. A
+// window without an end, left by a poll that failed during its acknowledgement, ends now.
+func (p *Poller[T]) replay(ctx context.Context, cp *Checkpoint) (int, error) {
+	end := cp.PendingEnd
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	p.logger.Info().
+		Str("feed", p.feed.Name).
+		Time("start", cp.PendingStart).
+		Time("end", end).
+		Msg("replaying athenahealth changed feed window")
+
+	records, err := p.feed.Fetch(ctx, Query{
+		ProcessedStart: cp.PendingStart.Add(-p.replayMargin).In(p.location),
+		ProcessedEnd:   end.Add(p.replayMargin).In(p.location),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetching processed records: %w", err)
+	}
+
+	n, err := p.dispatch(ctx, records)
+	if err != nil {
+		return n, err
+	}
+
+	return n, p.commit(ctx, cp, end)
+}
+
+func (p *Poller[T]) dispatch(ctx context.Context, records []T) (int, error) {
+	for i, record := range records {
+		err := p.handler(ctx, record)
+		if err != nil {
+			return i, fmt.Errorf("handling %s record: %w", p.feed.Name, err)
+		}
+	}
+
+	return len(records), nil
+}
+
+func (p *Poller[T]) commit(ctx context.Context, cp *Checkpoint, processedAt time.Time) error {
+	cp.LastProcessed = processedAt
+	cp.PendingStart = time.Time{}
+	cp.PendingEnd = time.Time{}
+
+	err := p.checkpoints.Save(ctx, p.feed.Name, cp)
+	if err != nil {
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// difference returns the records in a that are not in b. athenahealth records have no common
+// identifier, so records are compared by their JSON encoding.
+func difference[T any](a, b []T) ([]T, error) {
+	seen := make(map[string]int, len(b))
+
+	for _, record := range b {
+		key, err := recordKey(record)
+		if err != nil {
+			return nil, err
+		}
+
+		seen[key]++
+	}
+
+	var diff []T
+
+	for _, record := range a {
+		key, err := recordKey(record)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[key] > 0 {
+			seen[key]--
+			continue
+		}
+
+		diff = append(diff, record)
+	}
+
+	return diff, nil
+}
+
+func recordKey(record any) (string, error) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("marshaling record: %w", err)
+	}
+
+	return string(bytes.TrimSpace(b)), nil
+}
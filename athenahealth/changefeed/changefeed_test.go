@@ -0,0 +1,350 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecord struct {
+	ID int `json:"id"`
+}
+
+// testFeed simulates an athenahealth changed feed. Records added with add are unprocessed until
+// they are fetched without LeaveUnprocessed.
+type testFeed struct {
+	unprocessed []testRecord
+	processed   map[time.Time][]testRecord
+
+	// beforeAck, if set, is called before records are marked processed.
+	beforeAck func()
+
+	queries []Query
+
+	lock sync.Mutex
+}
+
+func newTestFeed(records ...testRecord) *testFeed {
+	return &testFeed{
+		unprocessed: records,
+		processed:   make(map[time.Time][]testRecord),
+	}
+}
+
+func (f *testFeed) add(records ...testRecord) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.unprocessed = append(f.unprocessed, records...)
+}
+
+func (f *testFeed) feed() Feed[testRecord] {
+	return Feed[testRecord]{
+		Name: "test",
+		Fetch: func(ctx context.Context, q Query) ([]testRecord, error) {
+			f.lock.Lock()
+			f.queries = append(f.queries, q)
+			beforeAck := f.beforeAck
+			f.lock.Unlock()
+
+			if !q.ProcessedEnd.IsZero() {
+				f.lock.Lock()
+				defer f.lock.Unlock()
+
+				var records []testRecord
+				for at, batch := range f.processed {
+					if !at.Before(q.ProcessedStart) && !at.After(q.ProcessedEnd) {
+						records = append(records, batch...)
+					}
+				}
+
+				return records, nil
+			}
+
+			if !q.LeaveUnprocessed && beforeAck != nil {
+				beforeAck()
+			}
+
+			f.lock.Lock()
+			defer f.lock.Unlock()
+
+			records := f.unprocessed
+
+			if !q.LeaveUnprocessed {
+				f.processed[time.Now()] = records
+				f.unprocessed = nil
+			}
+
+			return records, nil
+		},
+	}
+}
+
+func TestPoller_Poll(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1}, testRecord{ID: 2})
+	store := NewMemoryStore()
+
+	var handled []int
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		handled = append(handled, record.ID)
+		return nil
+	}, store)
+
+	before := time.Now()
+
+	n, err := poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 2}, handled)
+	assert.Empty(feed.unprocessed)
+
+	assert.Equal([]Query{{LeaveUnprocessed: true}, {}}, feed.queries)
+
+	cp, err := store.Load(context.Background(), "test")
+	assert.NoError(err)
+	assert.False(cp.pending())
+	assert.False(cp.LastProcessed.Before(before))
+
+	n, err = poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(0, n)
+	assert.Len(handled, 2)
+}
+
+func TestPoller_Poll_handlerError(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1}, testRecord{ID: 2})
+
+	handlerErr := errors.New("handler error")
+	fail := true
+
+	var handled []int
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		if record.ID == 2 && fail {
+			return handlerErr
+		}
+
+		handled = append(handled, record.ID)
+		return nil
+	}, NewMemoryStore())
+
+	n, err := poller.Poll(context.Background())
+	assert.ErrorIs(err, handlerErr)
+	assert.Equal(1, n)
+
+	// The records were not acknowledged, so they are delivered again.
+	assert.Len(feed.unprocessed, 2)
+
+	fail = false
+
+	n, err = poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 1, 2}, handled)
+	assert.Empty(feed.unprocessed)
+}
+
+func TestPoller_Poll_recordsArrivingBeforeAck(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1})
+	feed.beforeAck = func() {
+		feed.add(testRecord{ID: 2})
+		feed.beforeAck = nil
+	}
+
+	var handled []int
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		handled = append(handled, record.ID)
+		return nil
+	}, NewMemoryStore())
+
+	n, err := poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 2}, handled)
+}
+
+func TestPoller_Poll_replay(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1})
+	feed.beforeAck = func() {
+		feed.add(testRecord{ID: 2})
+		feed.beforeAck = nil
+	}
+
+	store := NewMemoryStore()
+	handlerErr := errors.New("handler error")
+	fail := true
+
+	var handled []int
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		if record.ID == 2 && fail {
+			return handlerErr
+		}
+
+		handled = append(handled, record.ID)
+		return nil
+	}, store).WithLocation(time.UTC)
+
+	_, err := poller.Poll(context.Background())
+	assert.ErrorIs(err, handlerErr)
+
+	// Record 2 was marked processed by the acknowledgement, so the window is saved for replay.
+	cp, err := store.Load(context.Background(), "test")
+	assert.NoError(err)
+	assert.True(cp.pending())
+	assert.Empty(feed.unprocessed)
+
+	fail = false
+
+	n, err := poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 1, 2}, handled)
+
+	replayQuery := feed.queries[2]
+	assert.False(replayQuery.LeaveUnprocessed)
+	assert.Equal(cp.PendingStart.Add(-defaultReplayMargin).In(time.UTC), replayQuery.ProcessedStart)
+	assert.Equal(cp.PendingEnd.Add(defaultReplayMargin).In(time.UTC), replayQuery.ProcessedEnd)
+
+	cp, err = store.Load(context.Background(), "test")
+	assert.NoError(err)
+	assert.False(cp.pending())
+}
+
+// failingStore fails to save checkpoints whose pending window has an end while fail is set.
+type failingStore struct {
+	*MemoryStore
+
+	fail bool
+}
+
+func (f *failingStore) Save(ctx context.Context, feed string, cp *Checkpoint) error {
+	if f.fail && !cp.PendingEnd.IsZero() {
+		return errors.New("store unavailable")
+	}
+
+	return f.MemoryStore.Save(ctx, feed, cp)
+}
+
+func TestPoller_Poll_saveErrorAfterAck(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1})
+	feed.beforeAck = func() {
+		feed.add(testRecord{ID: 2})
+		feed.beforeAck = nil
+	}
+
+	store := &failingStore{MemoryStore: NewMemoryStore(), fail: true}
+
+	var handled []int
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		handled = append(handled, record.ID)
+		return nil
+	}, store).WithLocation(time.UTC)
+
+	_, err := poller.Poll(context.Background())
+	assert.Error(err)
+	assert.Equal([]int{1}, handled)
+
+	// Record 2 was marked processed but not handled. The window saved before the
+	// acknowledgement has no end, so it is replayed up to the next poll.
+	cp, err := store.Load(context.Background(), "test")
+	assert.NoError(err)
+	assert.True(cp.pending())
+	assert.True(cp.PendingEnd.IsZero())
+	assert.Empty(feed.unprocessed)
+
+	store.fail = false
+
+	n, err := poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 1, 2}, handled)
+
+	cp, err = store.Load(context.Background(), "test")
+	assert.NoError(err)
+	assert.False(cp.pending())
+}
+
+func TestPoller_Poll_ackError(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1})
+	feed.beforeAck = func() {
+		feed.add(testRecord{ID: 2})
+		feed.beforeAck = nil
+	}
+
+	// The acknowledgement marks the records processed but its response is lost.
+	ackErr := errors.New("connection reset")
+	fetch := feed.feed().Fetch
+	failAck := true
+
+	store := NewMemoryStore()
+
+	var handled []int
+	poller := NewPoller(Feed[testRecord]{
+		Name: "test",
+		Fetch: func(ctx context.Context, q Query) ([]testRecord, error) {
+			records, err := fetch(ctx, q)
+			if failAck && !q.LeaveUnprocessed && q.ProcessedEnd.IsZero() {
+				failAck = false
+
+				return nil, ackErr
+			}
+
+			return records, err
+		},
+	}, func(ctx context.Context, record testRecord) error {
+		handled = append(handled, record.ID)
+		return nil
+	}, store).WithLocation(time.UTC)
+
+	_, err := poller.Poll(context.Background())
+	assert.ErrorIs(err, ackErr)
+	assert.Equal([]int{1}, handled)
+
+	n, err := poller.Poll(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 1, 2}, handled)
+}
+
+func TestPoller_Run(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := newTestFeed(testRecord{ID: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	poller := NewPoller(feed.feed(), func(ctx context.Context, record testRecord) error {
+		cancel()
+		return nil
+	}, NewMemoryStore()).WithInterval(time.Millisecond, 10*time.Millisecond)
+
+	err := poller.Run(ctx)
+	assert.ErrorIs(err, context.Canceled)
+	assert.Empty(feed.unprocessed)
+}
+
+func TestDifference(t *testing.T) {
+	assert := assert.New(t)
+
+	a := []testRecord{{ID: 1}, {ID: 2}, {ID: 2}, {ID: 3}}
+	b := []testRecord{{ID: 2}, {ID: 1}}
+
+	diff, err := difference(a, b)
+	assert.NoError(err)
+	assert.Equal([]testRecord{{ID: 2}, {ID: 3}}, diff)
+}
@@ -0,0 +1,66 @@
+package changefeed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the persisted progress of a feed.
+type Checkpoint struct {
+	// LastProcessed is when the last fully handled batch was marked processed in athenahealth.
+	LastProcessed time.Time `json:"lastProcessed"`
+
+	// PendingStart and PendingEnd bound a window in which records were marked processed in
+	// athenahealth but not all of them were handled. The window is replayed on the next poll.
+	// PendingEnd is zero if the poll stopped before its acknowledgement completed; the window then
+	// ends when it is replayed.
+	PendingStart time.Time `json:"pendingStart,omitzero"`
+	PendingEnd   time.Time `json:"pendingEnd,omitzero"`
+}
+
+func (c *Checkpoint) pending() bool {
+	return !c.PendingStart.IsZero()
+}
+
+// CheckpointStore persists feed checkpoints.
+type CheckpointStore interface {
+	// Load returns the checkpoint for feed, or nil if none has been saved.
+	Load(ctx context.Context, feed string) (*Checkpoint, error)
+	Save(ctx context.Context, feed string, cp *Checkpoint) error
+}
+
+// MemoryStore is a CheckpointStore that keeps checkpoints in memory. Checkpoints are lost when the
+// process exits.
+type MemoryStore struct {
+	checkpoints map[string]Checkpoint
+
+	lock sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+func (m *MemoryStore) Load(ctx context.Context, feed string) (*Checkpoint, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	cp, ok := m.checkpoints[feed]
+	if !ok {
+		return nil, nil
+	}
+
+	return &cp, nil
+}
+
+func (m *MemoryStore) Save(ctx context.Context, feed string, cp *Checkpoint) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.checkpoints[feed] = *cp
+
+	return nil
+}
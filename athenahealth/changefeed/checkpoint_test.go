@@ -0,0 +1,28 @@
+package changefeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewMemoryStore()
+
+	cp, err := store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Nil(cp)
+
+	expected := &Checkpoint{LastProcessed: time.Now()}
+
+	err = store.Save(context.Background(), "patients", expected)
+	assert.NoError(err)
+
+	cp, err = store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Equal(expected, cp)
+}
@@ -0,0 +1,158 @@
+package changefeed
+
+import (
+	"context"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+)
+
+// Names of the feeds returned by the constructors in this file.
+const (
+	FeedPatients      = "patients"
+	FeedAppointments  = "appointments"
+	FeedProblems      = "problems"
+	FeedPrescriptions = "prescriptions"
+	FeedLabResults    = "labresults"
+	FeedProviders     = "providers"
+)
+
+// PatientsFeed returns a Feed over ListChangedPatients. opts may be nil; its LeaveUnprocessed and
+// ShowProcessed{Start,End}Datetime fields are set by the Poller.
+func PatientsFeed(client athenahealth.Client, opts *athenahealth.ListChangedPatientOptions) Feed[*athenahealth.Patient] {
+	return Feed[*athenahealth.Patient]{
+		Name: FeedPatients,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.Patient, error) {
+			o := &athenahealth.ListChangedPatientOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			o.LeaveUnprocessed = q.LeaveUnprocessed
+			o.ShowProcessedStartDatetime = q.ProcessedStart
+			o.ShowProcessedEndDatetime = q.ProcessedEnd
+
+			return client.ListChangedPatients(ctx, o)
+		},
+	}
+}
+
+// AppointmentsFeed returns a Feed over ListChangedAppointments. opts may be nil; its
+// LeaveUnprocessed and ShowProcessed{Start,End}Datetime fields are set by the Poller.
+func AppointmentsFeed(client athenahealth.Client, opts *athenahealth.ListChangedAppointmentsOptions) Feed[*athenahealth.BookedAppointment] {
+	return Feed[*athenahealth.BookedAppointment]{
+		Name: FeedAppointments,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.BookedAppointment, error) {
+			o := &athenahealth.ListChangedAppointmentsOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			o.LeaveUnprocessed = q.LeaveUnprocessed
+			o.ShowProcessedStartDatetime = q.ProcessedStart
+			o.ShowProcessedEndDatetime = q.ProcessedEnd
+
+			return client.ListChangedAppointments(ctx, o)
+		},
+	}
+}
+
+// ProblemsFeed returns a Feed over ListChangedProblems. opts may be nil; its LeaveUnprocessed and
+// ShowProcessed{Start,End}Datetime fields are set by the Poller.
+func ProblemsFeed(client athenahealth.Client, opts *athenahealth.ListChangedProblemsOptions) Feed[*athenahealth.ChangedProblem] {
+	return Feed[*athenahealth.ChangedProblem]{
+		Name: FeedProblems,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.ChangedProblem, error) {
+			o := &athenahealth.ListChangedProblemsOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			o.LeaveUnprocessed = q.LeaveUnprocessed
+			o.ShowProcessedStartDatetime = q.ProcessedStart
+			o.ShowProcessedEndDatetime = q.ProcessedEnd
+
+			return client.ListChangedProblems(ctx, o)
+		},
+	}
+}
+
+// PrescriptionsFeed returns a Feed over ListChangedPrescriptions, fetching every page. opts may be
+// nil; its LeaveUnprocessed and ShowProcessed{Start,End}Datetime fields are set by the Poller.
+func PrescriptionsFeed(client athenahealth.Client, opts *athenahealth.ListChangedPrescriptionsOptions) Feed[*athenahealth.ChangedPrescription] {
+	return Feed[*athenahealth.ChangedPrescription]{
+		Name: FeedPrescriptions,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.ChangedPrescription, error) {
+			o := &athenahealth.ListChangedPrescriptionsOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			o.LeaveUnprocessed = q.LeaveUnprocessed
+			o.ShowProcessedStartDatetime = q.ProcessedStart
+			o.ShowProcessedEndDatetime = q.ProcessedEnd
+
+			var records []*athenahealth.ChangedPrescription
+
+			for record, err := range client.AllChangedPrescriptions(ctx, o) {
+				if err != nil {
+					return nil, err
+				}
+
+				records = append(records, record)
+			}
+
+			return records, nil
+		},
+	}
+}
+
+// LabResultsFeed returns a Feed over ListChangedLabResults, fetching every page. opts may be nil;
+// its LeaveUnprocessed and ShowProcessed{Start,End}DateTime fields are set by the Poller.
+func LabResultsFeed(client athenahealth.Client, opts *athenahealth.ListChangedLabResultsOptions) Feed[*athenahealth.ChangedLabResult] {
+	return Feed[*athenahealth.ChangedLabResult]{
+		Name: FeedLabResults,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.ChangedLabResult, error) {
+			o := &athenahealth.ListChangedLabResultsOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			leaveUnprocessed := q.LeaveUnprocessed
+			o.LeaveUnprocessed = &leaveUnprocessed
+			o.ShowProcessedStartDateTime = q.ProcessedStart
+			o.ShowProcessedEndDateTime = q.ProcessedEnd
+
+			var records []*athenahealth.ChangedLabResult
+
+			for record, err := range client.AllChangedLabResults(ctx, o) {
+				if err != nil {
+					return nil, err
+				}
+
+				records = append(records, record)
+			}
+
+			return records, nil
+		},
+	}
+}
+
+// ProvidersFeed returns a Feed over ListChangedProviders. opts may be nil; its LeaveUnprocessed
+// and ShowProcessed{Start,End}Datetime fields are set by the Poller.
+func ProvidersFeed(client athenahealth.Client, opts *athenahealth.ListChangedProviderOptions) Feed[*athenahealth.Provider] {
+	return Feed[*athenahealth.Provider]{
+		Name: FeedProviders,
+		Fetch: func(ctx context.Context, q Query) ([]*athenahealth.Provider, error) {
+			o := &athenahealth.ListChangedProviderOptions{}
+			if opts != nil {
+				*o = *opts
+			}
+
+			o.LeaveUnprocessed = q.LeaveUnprocessed
+			o.ShowProcessedStartDatetime = q.ProcessedStart
+			o.ShowProcessedEndDatetime = q.ProcessedEnd
+
+			return client.ListChangedProviders(ctx, o)
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileStore is a CheckpointStore that keeps the checkpoints of all feeds in a single JSON file.
+type FileStore struct {
+	path string
+
+	lock sync.Mutex
+}
+
+func NewFileStore(path string) *FileStore {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	return &FileStore{
+		path: path,
+	}
+}
+
+func (f *FileStore) Load(ctx context.Context, feed string) (*Checkpoint, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	cp, ok := checkpoints[feed]
+	if !ok {
+		return nil, nil
+	}
+
+	return cp, nil
+}
+
+func (f *FileStore) Save(ctx context.Context, feed string, cp *Checkpoint) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	checkpoints, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	checkpoints[feed] = cp
+
+	b, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(f.path, b, 0600)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileStore) read() (map[string]*Checkpoint, error) {
+	checkpoints := make(map[string]*Checkpoint)
+
+	contents, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return checkpoints, nil
+		}
+
+		return nil, err
+	}
+
+	if len(contents) == 0 {
+		return checkpoints, nil
+	}
+
+	err = json.Unmarshal(contents, &checkpoints)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling checkpoints: %s", err)
+	}
+
+	return checkpoints, nil
+}
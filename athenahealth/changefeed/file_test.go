@@ -0,0 +1,37 @@
+package changefeed
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+
+	cp, err := store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Nil(cp)
+
+	now := time.Now().UTC()
+	patients := &Checkpoint{LastProcessed: now}
+	providers := &Checkpoint{LastProcessed: now, PendingStart: now.Add(-time.Second), PendingEnd: now}
+
+	assert.NoError(store.Save(context.Background(), "patients", patients))
+	assert.NoError(store.Save(context.Background(), "providers", providers))
+
+	cp, err = store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.True(patients.LastProcessed.Equal(cp.LastProcessed))
+	assert.False(cp.pending())
+
+	cp, err = store.Load(context.Background(), "providers")
+	assert.NoError(err)
+	assert.True(providers.PendingStart.Equal(cp.PendingStart))
+	assert.True(cp.pending())
+}
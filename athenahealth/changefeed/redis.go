@@ -0,0 +1,63 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const RedisDefaultKeyPrefix = "athena_changefeed:"
+
+// RedisStore is a CheckpointStore that keeps each feed's checkpoint in a Redis key.
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+func NewRedisStore(client redis.UniversalClient, keyPrefix string) *RedisStore {
+	if client == nil {
+		panic("client is nil")
+	}
+
+	r := &RedisStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+
+	if len(r.keyPrefix) == 0 {
+		r.keyPrefix = RedisDefaultKeyPrefix
+	}
+
+	return r
+}
+
+func (r *RedisStore) Load(ctx context.Context, feed string) (*Checkpoint, error) {
+	val, err := r.client.Get(ctx, r.keyPrefix+feed).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	cp := &Checkpoint{}
+	err = json.Unmarshal(val, cp)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling checkpoint: %s", err)
+	}
+
+	return cp, nil
+}
+
+func (r *RedisStore) Save(ctx context.Context, feed string, cp *Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.keyPrefix+feed, b, 0).Err()
+}
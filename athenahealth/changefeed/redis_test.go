@@ -0,0 +1,61 @@
+package changefeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/redistest"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStore(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	store := NewRedisStore(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	cp, err := store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.Nil(cp)
+
+	expected := &Checkpoint{LastProcessed: time.Now().UTC()}
+
+	err = store.Save(context.Background(), "patients", expected)
+	assert.NoError(err)
+	assert.True(s.Exists(RedisDefaultKeyPrefix + "patients"))
+
+	cp, err = store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.True(expected.LastProcessed.Equal(cp.LastProcessed))
+}
+
+func TestRedisStore_cluster(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	store := NewRedisStore(redistest.NewClusterClient(s.Addr()), "")
+
+	expected := &Checkpoint{LastProcessed: time.Now().UTC()}
+
+	err = store.Save(context.Background(), "patients", expected)
+	assert.NoError(err)
+
+	cp, err := store.Load(context.Background(), "patients")
+	assert.NoError(err)
+	assert.True(expected.LastProcessed.Equal(cp.LastProcessed))
+}
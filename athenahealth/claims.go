@@ -3,6 +3,7 @@ package athenahealth
 import (
 	"context"
 	"encoding/json"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -108,18 +109,18 @@ func (h *HTTPClient) CreateFinancialClaim(ctx context.Context, opts *CreateClaim
 
 	form.Add("supervisingproviderid", opts.SupervisingProviderID)
 
-	res := &createClaimResponse{}
+	out := &createClaimResponse{}
 
-	_, err = h.PostForm(ctx, "/claims", form, res)
+	res, err := h.PostForm(ctx, "/claims", form, out)
 	if err != nil {
 		return []string{}, err
 	}
 
-	if !res.Success {
-		return []string{}, errors.New(res.ErrorMessage)
+	if !out.Success {
+		return []string{}, envelopeError(res)
 	}
 
-	return res.ClaimIDs, nil
+	return out.ClaimIDs, nil
 }
 
 type ClaimProcedure struct {
@@ -231,3 +232,18 @@ func (h *HTTPClient) ListClaims(ctx context.Context, opts *ListClaimsOptions) (*
 		Pagination: makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// AllClaims iterates over claims, calling ListClaims for each page as needed. Set
+// opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllClaims(ctx context.Context, opts *ListClaimsOptions) iter.Seq2[*Claim, error] {
+	pagination := func(o *ListClaimsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListClaimsOptions) ([]*Claim, *PaginationResult, error) {
+		res, err := h.ListClaims(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Claims, res.Pagination, nil
+	})
+}
@@ -3,7 +3,11 @@ package athenahealth
 import (
 	"context"
 	"io"
+	"iter"
+	"net/http"
 	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
 )
 
 // Client describes a client for the athenahealth API.
@@ -114,10 +118,11 @@ type Client interface {
 	GetProvider(ctx context.Context, providerID string) (*Provider, error)
 
 	// Subscription
-	GetSubscription(ctx context.Context, feedType string) (*Subscription, error)
-	ListSubscriptionEvents(ctx context.Context, feedType string) ([]*SubscriptionEvent, error)
-	Subscribe(ctx context.Context, feedType string, opts *SubscribeOptions) error
-	Unsubscribe(ctx context.Context, feedType string, opts *UnsubscribeOptions) error
+	GetSubscription(ctx context.Context, feedType FeedType) (*Subscription, error)
+	ListSubscriptionEvents(ctx context.Context, feedType FeedType) ([]*SubscriptionEvent, error)
+	Subscribe(ctx context.Context, feedType FeedType, opts *SubscribeOptions) error
+	Unsubscribe(ctx context.Context, feedType FeedType, opts *UnsubscribeOptions) error
+	EnsureSubscriptions(ctx context.Context, desired map[FeedType][]string) (*SubscriptionReport, error)
 
 	// List Changed
 	ListChangedPatients(context.Context, *ListChangedPatientOptions) ([]*Patient, error)
@@ -134,6 +139,21 @@ type Client interface {
 
 	// Telehealth
 	GetTelehealthInviteURL(ctx context.Context, apptID string) (*GetTelehealthInviteURLResult, error)
+
+	// Pagination iterators
+	AllBookedAppointments(ctx context.Context, opts *ListBookedAppointmentsOptions) iter.Seq2[*BookedAppointment, error]
+	AllOpenAppointmentSlots(ctx context.Context, departmentID int, opts *ListOpenAppointmentSlotOptions) iter.Seq2[*OpenAppointmentSlot, error]
+	AllClaims(ctx context.Context, opts *ListClaimsOptions) iter.Seq2[*Claim, error]
+	AllDepartments(ctx context.Context, opts *ListDepartmentsOptions) iter.Seq2[*Department, error]
+	AllAdminDocuments(ctx context.Context, patientID string, opts *ListAdminDocumentsOptions) iter.Seq2[*AdminDocument, error]
+	AllEncounterDocuments(ctx context.Context, departmentID, patientID string, opts *ListEncounterDocumentsOptions) iter.Seq2[*EncounterDocument, error]
+	AllPatientInsurancePackages(ctx context.Context, opts *ListPatientInsurancePackagesOptions) iter.Seq2[*InsurancePackage, error]
+	AllLabResults(ctx context.Context, patientID string, departmentID string, opts *ListLabResultsOptions) iter.Seq2[*LabResult, error]
+	AllChangedLabResults(ctx context.Context, opts *ListChangedLabResultsOptions) iter.Seq2[*ChangedLabResult, error]
+	AllPatients(ctx context.Context, opts *ListPatientsOptions) iter.Seq2[*Patient, error]
+	AllPatientsMatchingCustomField(ctx context.Context, opts *ListPatientsMatchingCustomFieldOptions) iter.Seq2[*Patient, error]
+	AllChangedPrescriptions(ctx context.Context, opts *ListChangedPrescriptionsOptions) iter.Seq2[*ChangedPrescription, error]
+	AllProviders(ctx context.Context, opts *ListProvidersOptions) iter.Seq2[*Provider, error]
 }
 
 type TokenProvider interface {
@@ -143,14 +163,47 @@ type TokenProvider interface {
 type TokenCacher interface {
 	Get(context.Context) (string, error)
 	Set(context.Context, string, time.Time) error
+	// Invalidate discards the cached token so the next Get returns tokencacher.ErrTokenNotExist.
+	Invalidate(context.Context) error
+}
+
+// TokenRefreshLocker is implemented by TokenCachers shared between instances that can coordinate
+// token refreshes, such as tokencacher.Redis with WithRefreshLock. On a cache miss HTTPClient
+// takes the lock before calling the TokenProvider; instances that find it held wait for the cache
+// to be populated instead.
+type TokenRefreshLocker interface {
+	// LockRefresh acquires the refresh lock and returns a function that releases it. It returns
+	// tokencacher.ErrRefreshLocked if another instance holds the lock.
+	LockRefresh(ctx context.Context) (unlock func(context.Context) error, err error)
 }
 
 type RateLimiter interface {
 	Allowed(ctx context.Context, preview bool) (retryAfter time.Duration, err error)
 }
 
+// RateLimitObserver is implemented by RateLimiters that adapt to athenahealth's responses, such as
+// ratelimiter.Adaptive. HTTPClient calls Observe with every response it receives.
+type RateLimitObserver interface {
+	Observe(ctx context.Context, preview bool, res *http.Response)
+}
+
 type Stats interface {
 	Request(method, path string) error
 	ResponseSuccess() error
 	ResponseError() error
 }
+
+// StatsV2 is implemented by Stats that record latency, status codes, bytes sent, rate limit waits
+// and token refreshes, such as stats.Datadog. If the Stats passed to HTTPClient.WithStats implement
+// it, HTTPClient calls StatsV2 instead of Stats' Request, ResponseSuccess and ResponseError.
+//
+// Errors returned by Stats and StatsV2 are logged; they never fail a request.
+type StatsV2 interface {
+	// RequestStarted is called before each attempt of a request is sent. Only the request's
+	// practice, environment, method, route and attempt are set.
+	RequestStarted(stat *stats.RequestStat) error
+	// RequestCompleted is called after each attempt of a request, whether or not it succeeded.
+	RequestCompleted(stat *stats.RequestStat) error
+	// TokenRefreshed is called after each call to the TokenProvider.
+	TokenRefreshed(stat *stats.TokenRefreshStat) error
+}
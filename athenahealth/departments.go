@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 )
@@ -144,3 +145,18 @@ func (h *HTTPClient) ListDepartments(ctx context.Context, opts *ListDepartmentsO
 		Pagination:  makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// AllDepartments iterates over departments, calling ListDepartments for each page as needed. Set
+// opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllDepartments(ctx context.Context, opts *ListDepartmentsOptions) iter.Seq2[*Department, error] {
+	pagination := func(o *ListDepartmentsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListDepartmentsOptions) ([]*Department, *PaginationResult, error) {
+		res, err := h.ListDepartments(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Departments, res.Pagination, nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"iter"
 	"net/url"
 	"strconv"
 )
@@ -87,6 +88,21 @@ func (h *HTTPClient) ListAdminDocuments(ctx context.Context, patientID string, o
 	}, nil
 }
 
+// AllAdminDocuments iterates over the patient's admin documents, calling ListAdminDocuments for
+// each page as needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllAdminDocuments(ctx context.Context, patientID string, opts *ListAdminDocumentsOptions) iter.Seq2[*AdminDocument, error] {
+	pagination := func(o *ListAdminDocumentsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListAdminDocumentsOptions) ([]*AdminDocument, *PaginationResult, error) {
+		res, err := h.ListAdminDocuments(ctx, patientID, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.AdminDocuments, res.Pagination, nil
+	})
+}
+
 type AddDocumentOptions struct {
 	ActionNote         *string
 	AppointmentID      *int
@@ -744,3 +760,18 @@ func (h *HTTPClient) ListEncounterDocuments(ctx context.Context, departmentID, p
 		Pagination:         makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// AllEncounterDocuments iterates over the patient's encounter documents in a department, calling
+// ListEncounterDocuments for each page as needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllEncounterDocuments(ctx context.Context, departmentID, patientID string, opts *ListEncounterDocumentsOptions) iter.Seq2[*EncounterDocument, error] {
+	pagination := func(o *ListEncounterDocumentsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListEncounterDocumentsOptions) ([]*EncounterDocument, *PaginationResult, error) {
+		res, err := h.ListEncounterDocuments(ctx, departmentID, patientID, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.EncounterDocuments, res.Pagination, nil
+	})
+}
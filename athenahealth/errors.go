@@ -1,5 +1,163 @@
 package athenahealth
 
-import "errors"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
 
-var ErrNotFound = errors.New("not found")
+// Sentinel errors wrapped by *APIError. Use errors.Is to check for them.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("conflict")
+	ErrServerError  = errors.New("server error")
+)
+
+// RateLimitError is wrapped by *APIError when athenahealth responds with a 429. It matches
+// ErrRateLimited with errors.Is.
+type RateLimitError struct {
+	// RetryAfter is the delay requested by the Retry-After header, or zero if it was not sent.
+	RetryAfter time.Duration
+}
+
+func (r *RateLimitError) Error() string {
+	if r.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", ErrRateLimited, r.RetryAfter)
+	}
+
+	return ErrRateLimited.Error()
+}
+
+func (r *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// statusError returns the error wrapped by an *APIError for an HTTP status code.
+func statusError(res *http.Response) error {
+	switch {
+	case res.StatusCode == http.StatusBadRequest, res.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+
+	case res.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+
+	case res.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+
+	case res.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+
+	case res.StatusCode == http.StatusConflict:
+		return ErrConflict
+
+	case res.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := parseRetryAfter(res.Header.Get("Retry-After"))
+
+		return &RateLimitError{RetryAfter: retryAfter}
+
+	case res.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+
+	return nil
+}
+
+// envelope is the body some athenahealth endpoints return with a 200 status to report whether
+// the operation succeeded. success is a bool on most endpoints and a string on some.
+type envelope struct {
+	Success      json.RawMessage `json:"success"`
+	ErrorMessage string          `json:"errormessage"`
+}
+
+func (e *envelope) failed() bool {
+	if len(e.ErrorMessage) > 0 {
+		return true
+	}
+
+	success := strings.Trim(string(e.Success), `"`)
+
+	return success == "false" || success == "0"
+}
+
+// checkEnvelope decodes the success/errormessage envelope from the body of res and returns an
+// *APIError if it reports a failure. Array bodies fail if any element does.
+func checkEnvelope(res *http.Response) error {
+	resBody, err := readResponseBody(res)
+	if err != nil {
+		return err
+	}
+
+	var envelopes []*envelope
+
+	trimmed := bytes.TrimSpace(resBody)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		err = json.Unmarshal(trimmed, &envelopes)
+	} else {
+		e := &envelope{}
+		err = json.Unmarshal(trimmed, e)
+		envelopes = append(envelopes, e)
+	}
+	if err != nil {
+		return fmt.Errorf("Error unmarshaling response body: %s", err)
+	}
+
+	for _, e := range envelopes {
+		if !e.failed() {
+			continue
+		}
+
+		message := e.ErrorMessage
+		if len(message) == 0 {
+			message = "unsuccessful response"
+		}
+
+		apiErr := &APIError{
+			Err:          ErrValidation,
+			AthenaError:  message,
+			HTTPResponse: res,
+			XRequestID:   requestID(res),
+		}
+
+		lower := strings.ToLower(message)
+		if strings.Contains(lower, "duplicate") || strings.Contains(lower, "already exist") {
+			apiErr.Err = ErrConflict
+		}
+
+		return apiErr
+	}
+
+	return nil
+}
+
+// envelopeError returns the error for a response whose envelope did not report success, even if
+// it did not include an error message either.
+func envelopeError(res *http.Response) error {
+	err := checkEnvelope(res)
+	if err != nil {
+		return err
+	}
+
+	return &APIError{
+		Err:          ErrValidation,
+		AthenaError:  "unsuccessful response",
+		HTTPResponse: res,
+		XRequestID:   requestID(res),
+	}
+}
+
+// requestID returns the X-Request-Id that was sent with the request that produced res.
+func requestID(res *http.Response) string {
+	if res == nil || res.Request == nil {
+		return ""
+	}
+
+	return res.Request.Header.Get(XRequestIDHeaderKey)
+}
@@ -0,0 +1,120 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_request_statusErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		status int
+		err    error
+	}{
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServerError},
+		{http.StatusServiceUnavailable, ErrServerError},
+	}
+
+	for _, test := range tests {
+		var xRequestID string
+		h := func(w http.ResponseWriter, r *http.Request) {
+			xRequestID = r.Header.Get(XRequestIDHeaderKey)
+			w.WriteHeader(test.status)
+		}
+
+		athenaClient, ts := testClient(h)
+
+		_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+		ts.Close()
+
+		assert.ErrorIs(err, test.err, "status %d", test.status)
+
+		apiErr := &APIError{}
+		assert.ErrorAs(err, &apiErr)
+		assert.Equal(xRequestID, apiErr.XRequestID)
+	}
+}
+
+func TestHTTPClient_request_RateLimitError(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	rateLimitErr := &RateLimitError{}
+	assert.ErrorAs(err, &rateLimitErr)
+	assert.Equal(7*time.Second, rateLimitErr.RetryAfter)
+	assert.True(errors.Is(err, ErrRateLimited))
+}
+
+func testEnvelopeResponse(body string) *http.Response {
+	req, _ := http.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(XRequestIDHeaderKey, "request-id")
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Request:    req,
+	}
+}
+
+func Test_checkEnvelope(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(checkEnvelope(testEnvelopeResponse(`{"success":true}`)))
+	assert.NoError(checkEnvelope(testEnvelopeResponse(`{"success":"true"}`)))
+	assert.NoError(checkEnvelope(testEnvelopeResponse(`[{"patientid":"1"}]`)))
+
+	err := checkEnvelope(testEnvelopeResponse(`{"success":false,"errormessage":"Invalid department."}`))
+	assert.ErrorIs(err, ErrValidation)
+
+	apiErr := &APIError{}
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal("Invalid department.", apiErr.AthenaError)
+	assert.Equal("request-id", apiErr.XRequestID)
+	assert.Equal(http.StatusOK, apiErr.HTTPResponse.StatusCode)
+
+	err = checkEnvelope(testEnvelopeResponse(`{"success":"false"}`))
+	assert.ErrorIs(err, ErrValidation)
+
+	err = checkEnvelope(testEnvelopeResponse(`[{"errormessage":"Patient already exists."}]`))
+	assert.ErrorIs(err, ErrConflict)
+
+	// The body can still be read after the envelope has been checked.
+	res := testEnvelopeResponse(`{"success":true}`)
+	assert.NoError(checkEnvelope(res))
+	b, _ := io.ReadAll(res.Body)
+	assert.Equal(`{"success":true}`, string(b))
+}
+
+func Test_envelopeError(t *testing.T) {
+	assert := assert.New(t)
+
+	err := envelopeError(testEnvelopeResponse(`{}`))
+	assert.ErrorIs(err, ErrValidation)
+
+	err = envelopeError(testEnvelopeResponse(`{"success":false,"errormessage":"Duplicate claim."}`))
+	assert.ErrorIs(err, ErrConflict)
+}
@@ -0,0 +1,87 @@
+// Package faults injects failures into HTTPClient requests for resilience testing.
+//
+// Transport is an http.RoundTripper that injects latency, error responses, connection resets and
+// truncated bodies into requests whose method and path match a rule, either with a probability or
+// following a script. RateLimiter does the same for ratelimiter.ErrRateExceeded. Both are passed
+// to an HTTPClient like their production counterparts:
+//
+//	transport := faults.NewTransport(nil).
+//		WithProbability("GET", `/patients`, 0.1, faults.ServiceUnavailable()).
+//		WithScript("", `/oauth2/`, faults.Latency(2*time.Second))
+//
+//	client := athenahealth.NewHTTPClient(&http.Client{Transport: transport}, practiceID, key, secret)
+package faults
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Fault describes a failure to inject into a request. Latency is applied first. If StatusCode is
+// set the request is not sent and an error response is returned; otherwise Reset fails the
+// request with a connection reset and Truncate cuts the real response body in half.
+type Fault struct {
+	Latency time.Duration
+
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header of the injected response, if set.
+	RetryAfter time.Duration
+	// Body is the body of the injected response. It defaults to an athenahealth error body.
+	Body string
+
+	Reset    bool
+	Truncate bool
+}
+
+// Latency delays the request by d.
+func Latency(d time.Duration) *Fault {
+	return &Fault{Latency: d}
+}
+
+// Status responds with statusCode without sending the request.
+func Status(statusCode int) *Fault {
+	return &Fault{StatusCode: statusCode}
+}
+
+// RateLimited responds with a 429 and a Retry-After header.
+func RateLimited(retryAfter time.Duration) *Fault {
+	return &Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// ServerError responds with a 500.
+func ServerError() *Fault {
+	return Status(http.StatusInternalServerError)
+}
+
+// ServiceUnavailable responds with a 503.
+func ServiceUnavailable() *Fault {
+	return Status(http.StatusServiceUnavailable)
+}
+
+// ConnectionReset fails the request as if the connection was reset by athenahealth.
+func ConnectionReset() *Fault {
+	return &Fault{Reset: true}
+}
+
+// TruncatedBody sends the request and cuts the response body in half, e.g. leaving JSON
+// unterminated.
+func TruncatedBody() *Fault {
+	return &Fault{Truncate: true}
+}
+
+func (f *Fault) String() string {
+	switch {
+	case f.StatusCode > 0:
+		return strconv.Itoa(f.StatusCode)
+
+	case f.Reset:
+		return "connection reset"
+
+	case f.Truncate:
+		return "truncated body"
+	}
+
+	return fmt.Sprintf("latency %s", f.Latency)
+}
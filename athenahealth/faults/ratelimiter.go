@@ -0,0 +1,107 @@
+package faults
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+)
+
+// AllowedFunc is the signature of athenahealth.RateLimiter's Allowed method.
+type AllowedFunc func(ctx context.Context, preview bool) (time.Duration, error)
+
+// RateLimiter is an athenahealth.RateLimiter that returns ratelimiter.ErrRateExceeded, exercising
+// HTTPClient's wait-and-retry loop without Redis. It is safe for concurrent use.
+type RateLimiter struct {
+	allowed AllowedFunc
+
+	probability float64
+	retryAfter  time.Duration
+	script      []time.Duration
+	rand        *rand.Rand
+	exceeded    int
+
+	lock sync.Mutex
+}
+
+// NewRateLimiter returns a RateLimiter that defers to allowed, or allows every request if allowed
+// is nil, when it does not inject a fault. Pass a real rate limiter's Allowed method to wrap it.
+func NewRateLimiter(allowed AllowedFunc) *RateLimiter {
+	if allowed == nil {
+		allowed = func(ctx context.Context, preview bool) (time.Duration, error) {
+			return 0, nil
+		}
+	}
+
+	return &RateLimiter{
+		allowed: allowed,
+		rand:    rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// WithSeed makes probabilistic injection deterministic.
+func (r *RateLimiter) WithSeed(seed uint64) *RateLimiter {
+	r.rand = rand.New(rand.NewPCG(seed, seed))
+
+	return r
+}
+
+// WithProbability rejects requests with the given probability (0 to 1), asking them to retry
+// after retryAfter.
+func (r *RateLimiter) WithProbability(probability float64, retryAfter time.Duration) *RateLimiter {
+	r.probability = probability
+	r.retryAfter = retryAfter
+
+	return r
+}
+
+// WithScript rejects successive calls with the given retry-after durations before applying
+// WithProbability. A zero duration allows the call.
+func (r *RateLimiter) WithScript(retryAfters ...time.Duration) *RateLimiter {
+	r.script = retryAfters
+
+	return r
+}
+
+// Exceeded returns the number of times ErrRateExceeded was returned.
+func (r *RateLimiter) Exceeded() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.exceeded
+}
+
+func (r *RateLimiter) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	retryAfter, ok := r.next()
+	if !ok {
+		return r.allowed(ctx, preview)
+	}
+
+	return retryAfter, ratelimiter.ErrRateExceeded
+}
+
+func (r *RateLimiter) next() (time.Duration, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.script) > 0 {
+		retryAfter := r.script[0]
+		r.script = r.script[1:]
+
+		if retryAfter > 0 {
+			r.exceeded++
+		}
+
+		return retryAfter, retryAfter > 0
+	}
+
+	if r.probability > 0 && r.rand.Float64() < r.probability {
+		r.exceeded++
+
+		return r.retryAfter, true
+	}
+
+	return 0, false
+}
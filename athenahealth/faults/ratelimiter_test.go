@@ -0,0 +1,65 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_script(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	rateLimiter := NewRateLimiter(nil).WithScript(time.Millisecond, 0, time.Millisecond, time.Millisecond)
+
+	client := newTestClient(srv, NewTransport(nil), 1).WithRateLimiter(rateLimiter)
+
+	// Rate limited requests wait and try again rather than failing.
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.Equal(1, rateLimiter.Exceeded())
+
+	_, err = client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.Equal(3, rateLimiter.Exceeded())
+}
+
+func TestRateLimiter_probability(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	rateLimiter := NewRateLimiter(nil).WithSeed(1).WithProbability(1, time.Second)
+
+	client := newTestClient(srv, NewTransport(nil), 1).WithRateLimiter(rateLimiter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetDepartment(ctx, "1")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.Equal(1, rateLimiter.Exceeded())
+}
+
+func TestRateLimiter_allowed(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	rateLimiter := NewRateLimiter(func(ctx context.Context, preview bool) (time.Duration, error) {
+		calls++
+
+		return time.Second, ratelimiter.ErrRateExceeded
+	})
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ratelimiter.ErrRateExceeded)
+	assert.Equal(time.Second, retryAfter)
+	assert.Equal(1, calls)
+	assert.Equal(0, rateLimiter.Exceeded())
+}
@@ -0,0 +1,224 @@
+package faults
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Transport is an http.RoundTripper that injects faults into matching requests and sends the rest
+// with its underlying transport. It is safe for concurrent use.
+type Transport struct {
+	transport http.RoundTripper
+	rules     []*rule
+	rand      *rand.Rand
+	injected  []Injection
+
+	lock sync.Mutex
+}
+
+// Injection records a fault injected by a Transport.
+type Injection struct {
+	Method string
+	Path   string
+	Fault  *Fault
+}
+
+// rule matches requests by method and path. A rule either injects its fault with a probability or
+// injects the next fault of its script, one per matching request, and passes requests through once
+// the script is exhausted.
+type rule struct {
+	method  string
+	pattern *regexp.Regexp
+
+	probability float64
+	fault       *Fault
+
+	script []*Fault
+}
+
+// NewTransport returns a Transport that sends requests with transport, or http.DefaultTransport if
+// transport is nil.
+func NewTransport(transport http.RoundTripper) *Transport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Transport{
+		transport: transport,
+		rand:      rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// WithSeed makes probabilistic injection deterministic.
+func (t *Transport) WithSeed(seed uint64) *Transport {
+	t.rand = rand.New(rand.NewPCG(seed, seed))
+
+	return t
+}
+
+// WithProbability injects fault into requests matching method and pattern with the given
+// probability (0 to 1). An empty method matches every method. pattern is a regular expression
+// matched against the URL path, e.g. `/patients/\d+$`; an empty pattern matches every path.
+func (t *Transport) WithProbability(method, pattern string, probability float64, fault *Fault) *Transport {
+	if fault == nil {
+		panic("fault is nil")
+	}
+
+	t.rules = append(t.rules, &rule{
+		method:      method,
+		pattern:     regexp.MustCompile(pattern),
+		probability: probability,
+		fault:       fault,
+	})
+
+	return t
+}
+
+// WithScript injects faults, in order, into successive requests matching method and pattern (see
+// WithProbability). A nil fault lets the request through, so WithScript("", "", ServerError(), nil,
+// ServerError()) fails the first and third requests.
+func (t *Transport) WithScript(method, pattern string, faults ...*Fault) *Transport {
+	t.rules = append(t.rules, &rule{
+		method:  method,
+		pattern: regexp.MustCompile(pattern),
+		script:  faults,
+	})
+
+	return t
+}
+
+// Injected returns the faults injected so far.
+func (t *Transport) Injected() []Injection {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]Injection(nil), t.injected...)
+}
+
+// next returns the fault to inject into req, if any. The first matching rule decides.
+func (t *Transport) next(req *http.Request) *Fault {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, r := range t.rules {
+		if len(r.method) > 0 && !strings.EqualFold(r.method, req.Method) {
+			continue
+		}
+
+		if !r.pattern.MatchString(req.URL.Path) {
+			continue
+		}
+
+		var fault *Fault
+
+		if r.fault != nil {
+			if t.rand.Float64() < r.probability {
+				fault = r.fault
+			}
+		} else if len(r.script) > 0 {
+			fault = r.script[0]
+			r.script = r.script[1:]
+		} else {
+			continue
+		}
+
+		if fault != nil {
+			t.injected = append(t.injected, Injection{
+				Method: req.Method,
+				Path:   req.URL.Path,
+				Fault:  fault,
+			})
+		}
+
+		return fault
+	}
+
+	return nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.next(req)
+	if fault == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+
+		case <-time.After(fault.Latency):
+		}
+	}
+
+	switch {
+	case fault.StatusCode > 0:
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return fault.response(req), nil
+
+	case fault.Reset:
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+
+	res, err := t.transport.RoundTrip(req)
+	if err != nil || !fault.Truncate {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	body = body[:len(body)/2]
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Del("Content-Length")
+
+	return res, nil
+}
+
+func (f *Fault) response(req *http.Request) *http.Response {
+	body := f.Body
+	if len(body) == 0 {
+		body = fmt.Sprintf(`{"error":%q}`, http.StatusText(f.StatusCode))
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	if f.RetryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second)/time.Second)))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,177 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/athenatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenProvider struct{}
+
+func (t *tokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	return athenatest.Token, time.Now().Add(time.Hour), nil
+}
+
+func newTestClient(srv *athenatest.Server, transport http.RoundTripper, maxAttempts int) *athenahealth.HTTPClient {
+	retryPolicy := athenahealth.NewRetryPolicy()
+	retryPolicy.MaxAttempts = maxAttempts
+	retryPolicy.BaseDelay = time.Millisecond
+	retryPolicy.MaxDelay = time.Millisecond
+
+	return athenahealth.NewHTTPClient(&http.Client{Transport: transport}, athenatest.PracticeID, "key", "secret").
+		WithBaseURL(srv.URL + "/v1/").
+		WithTokenProvider(&tokenProvider{}).
+		WithRetryPolicy(retryPolicy)
+}
+
+func newTestServer() *athenatest.Server {
+	srv := athenatest.NewServer()
+	srv.AddDepartment(&athenahealth.Department{DepartmentID: "1", Name: "Main"})
+
+	return srv
+}
+
+func TestTransport_script(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	transport := NewTransport(nil).
+		WithScript(http.MethodGet, `/departments/\d+$`, ServiceUnavailable(), nil, RateLimited(0))
+
+	client := newTestClient(srv, transport, 3)
+
+	department, err := client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.Equal("Main", department.Name)
+
+	department, err = client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.Equal("Main", department.Name)
+
+	injected := transport.Injected()
+	assert.Len(injected, 2)
+	assert.Equal(http.StatusServiceUnavailable, injected[0].Fault.StatusCode)
+	assert.Equal(http.StatusTooManyRequests, injected[1].Fault.StatusCode)
+	assert.Equal("/v1/"+athenatest.PracticeID+"/departments/1", injected[0].Path)
+
+	// The script is exhausted.
+	_, err = client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.Len(transport.Injected(), 2)
+}
+
+func TestTransport_statusWithoutRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := newTestClient(srv, NewTransport(nil).WithScript("", "", ServerError()), 1)
+
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.ErrorIs(err, athenahealth.ErrServerError)
+}
+
+func TestTransport_rateLimitedRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	transport := NewTransport(nil).WithScript("", "", RateLimited(2*time.Second))
+
+	req := httptest.NewRequest(http.MethodGet, "https://athenahealth.invalid/v1/1/patients", nil)
+
+	res, err := transport.RoundTrip(req)
+	assert.NoError(err)
+	assert.Equal(http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal("2", res.Header.Get("Retry-After"))
+}
+
+func TestTransport_connectionReset(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := newTestClient(srv, NewTransport(nil).WithScript("", "", ConnectionReset()), 1)
+
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.True(errors.Is(err, syscall.ECONNRESET))
+
+	// Idempotent requests are retried after transport errors.
+	client = newTestClient(srv, NewTransport(nil).WithScript("", "", ConnectionReset()), 2)
+
+	_, err = client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+}
+
+func TestTransport_truncatedBody(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := newTestClient(srv, NewTransport(nil).WithScript("", "", TruncatedBody()), 1)
+
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.ErrorContains(err, "unmarshaling")
+}
+
+func TestTransport_latency(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := newTestClient(srv, NewTransport(nil).WithScript("", "", Latency(time.Second)), 1).
+		WithRequestTimeout(10 * time.Millisecond)
+
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	client = newTestClient(srv, NewTransport(nil).WithScript("", "", Latency(10*time.Millisecond)), 1)
+
+	start := time.Now()
+	_, err = client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+	assert.GreaterOrEqual(time.Since(start), 10*time.Millisecond)
+}
+
+func TestTransport_probability(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newTestServer()
+	defer srv.Close()
+
+	transport := NewTransport(nil).
+		WithSeed(1).
+		WithProbability(http.MethodPost, "", 1, ServerError()).
+		WithProbability("", `/departments$`, 1, ServerError()).
+		WithProbability("", "", 0, ServerError())
+
+	client := newTestClient(srv, transport, 1)
+
+	_, err := client.GetDepartment(context.Background(), "1")
+	assert.NoError(err)
+
+	_, err = client.ListDepartments(context.Background(), nil)
+	assert.ErrorIs(err, athenahealth.ErrServerError)
+
+	assert.Len(transport.Injected(), 1)
+}
+
+func TestTransport_WithProbability_nilFault(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewTransport(nil).WithProbability("", "", 1, nil)
+	})
+}
@@ -354,13 +354,13 @@ func (h *HTTPClient) UpdateHealthHistoryFormForAppointment(ctx context.Context,
 
 	out := &ErrorMessageResponse{}
 
-	_, err = h.PutForm(ctx, fmt.Sprintf("/appointments/%s/healthhistoryforms/%s", url.QueryEscape(appointmentID), url.QueryEscape(formID)), payload, out)
+	res, err := h.PutForm(ctx, fmt.Sprintf("/appointments/%s/healthhistoryforms/%s", url.QueryEscape(appointmentID), url.QueryEscape(formID)), payload, out)
 	if err != nil {
 		return fmt.Errorf("updating health history form for appointment: %w", err)
 	}
 
 	if !out.Success {
-		return fmt.Errorf("error returned from athena: %s", out.Message)
+		return fmt.Errorf("updating health history form for appointment: %w", envelopeError(res))
 	}
 
 	return nil
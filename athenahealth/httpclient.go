@@ -14,12 +14,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokenprovider"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
@@ -47,6 +51,7 @@ type HTTPClient struct {
 	secret         string
 	preview        bool
 	baseURL        string
+	customBaseURL  string
 	requestTimeout time.Duration
 
 	tokenProvider TokenProvider
@@ -54,8 +59,14 @@ type HTTPClient struct {
 	rateLimiter   RateLimiter
 	stats         Stats
 	logger        *zerolog.Logger
-
-	requestLock sync.Mutex
+	retryPolicy   *RetryPolicy
+	middleware    []Middleware
+	tracer        trace.Tracer
+
+	tokenLock      sync.Mutex
+	tokenFetch     *tokenFetch
+	tokenExpiresAt time.Time
+	tokenRefresher *tokenRefresher
 }
 
 var _ Client = (*HTTPClient)(nil)
@@ -79,6 +90,7 @@ func NewHTTPClient(httpClient *http.Client, practiceID, clientID, secret string)
 		rateLimiter:   ratelimiter.NewDefault(),
 		stats:         stats.NewDefault(),
 		logger:        &noplogger,
+		tracer:        noop.NewTracerProvider().Tracer(tracerName),
 	}
 
 	c.setBaseURL()
@@ -86,14 +98,39 @@ func NewHTTPClient(httpClient *http.Client, practiceID, clientID, secret string)
 	return c
 }
 
+// Identity returns the client ID, practice ID and environment of the client, e.g. for
+// tokencacher.NewRedisForIdentity and ratelimiter.NewRedisForIdentity.
+func (h *HTTPClient) Identity() identity.Identity {
+	return identity.Identity{
+		ClientID:   h.clientID,
+		PracticeID: h.practiceID,
+		Preview:    h.preview,
+	}
+}
+
 func (h *HTTPClient) setBaseURL() {
-	if h.preview {
+	if len(h.customBaseURL) > 0 {
+		h.baseURL = fmt.Sprintf("%s%s", h.customBaseURL, h.practiceID)
+	} else if h.preview {
 		h.baseURL = fmt.Sprintf("%s%s", PreviewBaseURL, h.practiceID)
 	} else {
 		h.baseURL = fmt.Sprintf("%s%s", ProdBaseURL, h.practiceID)
 	}
 }
 
+// call holds the state of an athenahealth API request across attempts.
+type call struct {
+	method  string
+	path    string
+	url     string
+	headers http.Header
+	out     interface{}
+
+	attempt       int
+	xRequestID    string
+	rateLimitWait time.Duration
+}
+
 func (h *HTTPClient) request(ctx context.Context, method, path string, body io.Reader, headers http.Header, out interface{}) (*http.Response, error) {
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
@@ -101,104 +138,280 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 		defer cancel()
 	}
 
-	var token string
-	var err error
-	var expiresAt time.Time
-
-	h.requestLock.Lock()
-
 	if !strings.HasPrefix(path, "/") {
 		path = fmt.Sprintf("/%s", path)
 	}
 
-	reqURL := fmt.Sprintf("%s%s", h.baseURL, path)
+	c := &call{
+		method:  method,
+		path:    path,
+		url:     fmt.Sprintf("%s%s", h.baseURL, path),
+		headers: headers,
+		out:     out,
+	}
 
-	retryAfter, err := h.rateLimiter.Allowed(ctx, h.preview)
-	if err != nil {
-		h.requestLock.Unlock()
+	ctx, span := h.startSpan(ctx, c)
+
+	res, err := h.execute(ctx, c, body)
+
+	endSpan(span, c, res, err)
+
+	return res, err
+}
+
+// execute performs attempts of c until one succeeds or the error is not retryable.
+func (h *HTTPClient) execute(ctx context.Context, c *call, body io.Reader) (*http.Response, error) {
+	nextBody, replayable := replayableBody(body)
+
+	reauthenticated := false
+
+	for c.attempt = 1; ; c.attempt++ {
+		res, err := h.do(ctx, c, nextBody())
+		if err == nil {
+			return res, nil
+		}
+
+		// athenahealth may reject a token before its advertised expiration (e.g. if it was
+		// revoked). Discard the cached token and, if the body can be replayed, retry once with a
+		// freshly provided one.
+		if !reauthenticated && isUnauthorized(err) {
+			reauthenticated = true
 
-		if errors.Is(err, ratelimiter.ErrRateExceeded) {
 			h.logger.Info().
-				Str("method", method).
-				Str("url", reqURL).
-				Err(err).
-				Msg("athenahealth API request rate limited")
+				Str("method", c.method).
+				Str("url", c.url).
+				Msg("athenahealth API request unauthorized, invalidating cached token")
 
-			select {
-			case <-ctx.Done():
-				return nil, fmt.Errorf("waiting for rate limit retry interval: %w", ctx.Err())
+			invalidateErr := h.tokenCacher.Invalidate(ctx)
+			if invalidateErr != nil {
+				return res, errors.Join(err, invalidateErr)
+			}
 
-			case <-time.After(retryAfter):
-				return h.request(ctx, method, path, body, headers, out)
+			if !replayable {
+				h.logger.Info().
+					Str("method", c.method).
+					Str("url", c.url).
+					Msg("athenahealth API request body is not replayable, not retrying")
+
+				return res, err
 			}
+
+			continue
+		}
+
+		// The reauthenticated attempt does not count against the retry policy's MaxAttempts.
+		retryAttempt := c.attempt
+		if reauthenticated {
+			retryAttempt--
+		}
+
+		delay, retry := h.retryPolicy.retryDelay(ctx, c.method, retryAttempt, err)
+		if retry && !replayable {
+			h.logger.Info().
+				Str("method", c.method).
+				Str("url", c.url).
+				Msg("athenahealth API request body is not replayable, not retrying")
+
+			retry = false
 		}
 
+		if !retry {
+			var tErr *transportError
+			if errors.As(err, &tErr) {
+				err = tErr.err
+			}
+
+			return res, err
+		}
+
+		h.logger.Info().
+			Str("method", c.method).
+			Str("url", c.url).
+			Int("attempt", c.attempt).
+			Str("delay", delay.String()).
+			Err(err).
+			Msg("athenahealth API request failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return res, fmt.Errorf("waiting for retry interval: %w", ctx.Err())
+
+		case <-time.After(delay):
+		}
+	}
+}
+
+func isUnauthorized(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && errors.Is(apiErr.Err, ErrUnauthorized)
+}
+
+// allow blocks until the rate limiter allows c to proceed.
+func (h *HTTPClient) allow(ctx context.Context, c *call) error {
+	ctx, span := h.tracer.Start(ctx, "athenahealth.rate_limit")
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		wait := time.Since(start)
+		c.rateLimitWait += wait
+
+		span.SetAttributes(attribute.Int64(attrRateLimitWaitMS, wait.Milliseconds()))
+	}()
+
+	for {
+		retryAfter, err := h.rateLimiter.Allowed(ratelimiter.ContextWithRoute(ctx, c.method, c.path), h.preview)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, ratelimiter.ErrRateExceeded) {
+			recordSpanError(span, err)
+
+			return err
+		}
+
+		h.logger.Info().
+			Str("method", c.method).
+			Str("url", c.url).
+			Err(err).
+			Msg("athenahealth API request rate limited")
+
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("waiting for rate limit retry interval: %w", ctx.Err())
+			recordSpanError(span, err)
+
+			return err
+
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// do performs a single attempt of c.
+func (h *HTTPClient) do(ctx context.Context, c *call, body io.Reader) (*http.Response, error) {
+	rateLimitWait := c.rateLimitWait
+
+	err := h.allow(ctx, c)
+	if err != nil {
 		return nil, err
 	}
 
-	token, err = h.tokenCacher.Get(ctx)
+	token, err := h.token(ctx)
 	if err != nil {
-		if !errors.Is(err, tokencacher.ErrTokenNotExist) && !errors.Is(err, tokencacher.ErrTokenExpired) {
-			h.requestLock.Unlock()
-			return nil, err
-		}
+		return nil, err
+	}
 
-		token, expiresAt, err = h.tokenProvider.Provide(ctx)
-		if err != nil {
-			h.requestLock.Unlock()
-			return nil, err
+	headers := http.Header{}
+	if c.headers != nil {
+		headers = c.headers.Clone()
+	}
+
+	c.xRequestID = uuid.NewString()
+
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	headers.Add("User-Agent", userAgent)
+	headers.Set(XRequestIDHeaderKey, c.xRequestID)
+
+	req := &Request{
+		Method:  c.method,
+		Path:    c.path,
+		URL:     c.url,
+		Header:  headers,
+		Body:    body,
+		Attempt: c.attempt,
+	}
+
+	stat := h.newRequestStat(c)
+	h.requestStarted(stat)
+
+	start := time.Now()
+
+	res, err := h.handler()(contextWithRequestStat(ctx, stat), req)
+
+	stat.Duration = time.Since(start)
+	stat.RateLimitWait = c.rateLimitWait - rateLimitWait
+	if res != nil {
+		stat.StatusCode = res.StatusCode
+	}
+
+	h.requestCompleted(stat)
+
+	if observer, ok := h.rateLimiter.(RateLimitObserver); ok && res != nil {
+		observer.Observe(ratelimiter.ContextWithRoute(ctx, c.method, c.path), h.preview, res)
+	}
+
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && len(apiErr.XRequestID) == 0 {
+			apiErr.XRequestID = c.xRequestID
 		}
 
-		// Remove 1 minute from the expiration time to create a buffer to see
-		// if it resolves intermittent 401s.
-		err = h.tokenCacher.Set(context.Background(), token, expiresAt.Add(-1*time.Minute))
+		return res, err
+	}
+
+	resBody, err := readResponseBody(res)
+	if err != nil {
+		return res, err
+	}
+
+	// A middleware may have short-circuited the request with an error response.
+	if isErrorResponse(res) {
+		apiErr := newAPIError(res, resBody)
+		apiErr.XRequestID = c.xRequestID
+
+		return res, apiErr
+	}
+
+	if c.out != nil {
+		err = json.Unmarshal(resBody, c.out)
 		if err != nil {
-			h.requestLock.Unlock()
-			return nil, err
+			return res, fmt.Errorf("Error unmarshaling response body: %s", err)
 		}
 	}
 
-	h.requestLock.Unlock()
+	return res, nil
+}
 
+// send is the innermost RequestHandler. It sends req using the underlying *http.Client, buffers
+// the response body and converts error responses into an *APIError.
+func (h *HTTPClient) send(ctx context.Context, req *Request) (*http.Response, error) {
+	body := req.Body
 	if body != nil {
 		body = newSizeRecordingReader(body)
 	}
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
 	if err != nil {
 		return nil, err
 	}
 
-	if headers != nil {
-		req.Header = headers
-
-		// Go's http lib honors Content-Length on the Request struct above the header
-		if cl := req.Header.Get("Content-Length"); len(cl) > 0 {
-			parsed, err := strconv.ParseInt(cl, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid Content-Length header: %s", err)
-			}
+	httpReq.Header = req.Header
 
-			req.ContentLength = parsed
+	// Go's http lib honors Content-Length on the Request struct above the header
+	if cl := httpReq.Header.Get("Content-Length"); len(cl) > 0 {
+		parsed, err := strconv.ParseInt(cl, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Content-Length header: %s", err)
 		}
-	}
 
-	xRequestID := uuid.NewString()
+		httpReq.ContentLength = parsed
+	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Add("User-Agent", userAgent)
-	req.Header.Set(XRequestIDHeaderKey, xRequestID)
+	xRequestID := httpReq.Header.Get(XRequestIDHeaderKey)
 
 	h.logger.Info().
-		Str("method", method).
-		Str("url", reqURL).
+		Str("method", req.Method).
+		Str("url", req.URL).
 		Str("xRequestId", xRequestID).
 		Msg("athenahealth API request")
 
 	requestStart := time.Now()
 
-	res, err := h.httpClient.Do(req)
+	res, err := h.httpClient.Do(httpReq)
 	if err != nil {
-		return res, err
+		return res, &transportError{err: err}
 	}
 	defer func() { _ = res.Body.Close() }()
 
@@ -209,54 +422,31 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 
 	requestDuration := time.Since(requestStart)
 
-	err = h.stats.Request(method, path)
-	if err != nil {
-		return res, err
-	}
+	h.responseReceived(req, res)
 
-	responseError := res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices
-	if responseError {
-		err = h.stats.ResponseError()
-		if err != nil {
-			return res, err
-		}
-	} else {
-		err = h.stats.ResponseSuccess()
-		if err != nil {
-			return res, err
-		}
-	}
-
-	resBody, err := io.ReadAll(res.Body)
+	resBody, err := readResponseBody(res)
 	if err != nil {
 		return res, err
 	}
-	// close original req.Body before before overwriting
-	_ = res.Body.Close()
 
-	res.Body = io.NopCloser(bytes.NewBuffer(resBody))
+	if stat := requestStatFromContext(ctx); stat != nil {
+		stat.BytesSent = requestBodyLength
+		stat.BytesReceived = int64(len(resBody))
+	}
 
 	h.logger.Info().
-		Str("method", method).
-		Str("url", reqURL).
+		Str("method", req.Method).
+		Str("url", req.URL).
 		Int("statusCode", res.StatusCode).
 		Int("responseBodyLength", len(resBody)).
 		Int64("requestBodyLength", requestBodyLength).
-		Int64("requestContentLength", req.ContentLength).
+		Int64("requestContentLength", httpReq.ContentLength).
 		Str("xRequestId", xRequestID).
 		Str("duration", requestDuration.String()).
 		Msg("athenahealth API response")
 
-	if responseError {
-		err := &APIError{}
-		if res.StatusCode == http.StatusNotFound {
-			err.Err = ErrNotFound
-		}
-
-		//nolint
-		json.Unmarshal(resBody, err)
-
-		err.HTTPResponse = res
+	if isErrorResponse(res) {
+		err := newAPIError(res, resBody)
 
 		h.logger.Info().
 			Str("athenaError", err.AthenaError).
@@ -266,14 +456,44 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 		return res, err
 	}
 
-	if out != nil {
-		err = json.Unmarshal(resBody, out)
-		if err != nil {
-			return res, fmt.Errorf("Error unmarshaling response body: %s", err)
-		}
+	return res, nil
+}
+
+func isErrorResponse(res *http.Response) bool {
+	return res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices
+}
+
+// readResponseBody reads the entire response body and replaces it with an in-memory copy so it
+// can be read again.
+func readResponseBody(res *http.Response) ([]byte, error) {
+	if res.Body == nil {
+		return nil, nil
 	}
 
-	return res, nil
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	// close original res.Body before overwriting
+	_ = res.Body.Close()
+
+	res.Body = io.NopCloser(bytes.NewBuffer(resBody))
+
+	return resBody, nil
+}
+
+func newAPIError(res *http.Response, resBody []byte) *APIError {
+	err := &APIError{
+		Err: statusError(res),
+	}
+
+	//nolint
+	json.Unmarshal(resBody, err)
+
+	err.HTTPResponse = res
+	err.XRequestID = requestID(res)
+
+	return err
 }
 
 type sizeRecordingReader struct {
@@ -304,13 +524,34 @@ func (h *HTTPClient) WithPreview(preview bool) *HTTPClient {
 	h.preview = preview
 	h.setBaseURL()
 
-	if _, ok := h.tokenProvider.(*tokenprovider.Default); ok {
-		h.tokenProvider = tokenprovider.NewDefault(h.httpClient, h.clientID, h.secret, preview)
+	if d, ok := h.tokenProvider.(*tokenprovider.Default); ok {
+		d.WithPreview(preview)
+	}
+
+	return h
+}
+
+// WithCredentialsSource makes the default token provider get the client ID and secret from source
+// on every token fetch instead of using those passed to NewHTTPClient, so rotated secrets are used
+// on the next token refresh. It has no effect if WithTokenProvider was used. Identity still reports
+// the client ID passed to NewHTTPClient.
+func (h *HTTPClient) WithCredentialsSource(source tokenprovider.CredentialsSource) *HTTPClient {
+	if d, ok := h.tokenProvider.(*tokenprovider.Default); ok {
+		d.WithCredentialsSource(source)
 	}
 
 	return h
 }
 
+// WithBaseURL sends requests to baseURL instead of PreviewBaseURL or ProdBaseURL, e.g. to use a fake
+// server in tests. Like those, baseURL must end with a slash and is followed by the practice ID.
+func (h *HTTPClient) WithBaseURL(baseURL string) *HTTPClient {
+	h.customBaseURL = baseURL
+	h.setBaseURL()
+
+	return h
+}
+
 func (h *HTTPClient) WithTokenProvider(provider TokenProvider) *HTTPClient {
 	h.tokenProvider = provider
 
@@ -335,6 +576,15 @@ func (h *HTTPClient) WithStats(stats Stats) *HTTPClient {
 	return h
 }
 
+// WithRetryPolicy enables automatic retries of failed requests. Retries share the request's
+// context deadline, so the request timeout bounds the total time spent across all attempts.
+// A nil policy disables retries.
+func (h *HTTPClient) WithRetryPolicy(policy *RetryPolicy) *HTTPClient {
+	h.retryPolicy = policy
+
+	return h
+}
+
 func (h *HTTPClient) WithRequestTimeout(requestTimeout time.Duration) *HTTPClient {
 	h.requestTimeout = requestTimeout
 
@@ -420,13 +670,16 @@ func (h *HTTPClient) DeleteForm(ctx context.Context, path string, v url.Values,
 	return h.request(ctx, http.MethodDelete, path, body, headers, out)
 }
 
-// APIError represents an error response from the athenahealth API.
+// APIError represents an error response from the athenahealth API. Err is one of the sentinel
+// errors in errors.go (or a *RateLimitError) and can be checked with errors.Is and errors.As.
 type APIError struct {
 	Err                   error  `json:"-"`
 	AthenaError           string `json:"error"`
 	AthenaDetailedMessage string `json:"detailedmessage"`
 
-	HTTPResponse *http.Response
+	HTTPResponse *http.Response `json:"-"`
+	// XRequestID is the X-Request-Id sent with the failed request.
+	XRequestID string `json:"-"`
 }
 
 func (a *APIError) Error() string {
@@ -450,6 +703,10 @@ func (a *APIError) Unwrap() error {
 type PaginationOptions struct {
 	Limit  int
 	Offset int
+
+	// MaxItems caps the number of items yielded by the All* iterators. It is ignored by the
+	// List* methods.
+	MaxItems int
 }
 
 type PaginationResult struct {
@@ -13,10 +13,14 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
 	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokenprovider"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,9 +53,14 @@ func testClient(h http.HandlerFunc) (*HTTPClient, *httptest.Server) {
 }
 
 type testTokenProvider struct {
+	token string
 }
 
 func (t *testTokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	if len(t.token) > 0 {
+		return t.token, time.Now().Add(time.Minute * 5), nil
+	}
+
 	return testToken, time.Now().Add(time.Minute * 1), nil
 }
 
@@ -66,11 +75,22 @@ func (t *testTokenCacher) Set(context.Context, string, time.Time) error {
 	return nil
 }
 
+func (t *testTokenCacher) Invalidate(context.Context) error {
+	return nil
+}
+
 type testRateLimiter struct {
 	AllowedFunc func(preview bool) (time.Duration, error)
+
+	ctx  context.Context
+	lock sync.Mutex
 }
 
 func (t *testRateLimiter) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	t.lock.Lock()
+	t.ctx = ctx
+	t.lock.Unlock()
+
 	if t.AllowedFunc != nil {
 		return t.AllowedFunc(preview)
 	}
@@ -78,6 +98,14 @@ func (t *testRateLimiter) Allowed(ctx context.Context, preview bool) (time.Durat
 	return 0, nil
 }
 
+// lastContext returns the context passed to the last call to Allowed.
+func (t *testRateLimiter) lastContext() context.Context {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.ctx
+}
+
 type testStats struct {
 	RequestFunc         func(method, path string) error
 	ResponseSuccessFunc func() error
@@ -202,6 +230,162 @@ func TestHTTPClient_request_error(t *testing.T) {
 	assert.IsType(&APIError{}, err)
 }
 
+func TestHTTPClient_request_unauthorized(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"msg":"Hello World!"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	tokenCacher := tokencacher.NewDefault()
+	_ = tokenCacher.Set(context.Background(), "revoked-token", time.Now().Add(time.Hour))
+
+	athenaClient.WithTokenCacher(tokenCacher)
+	athenaClient.WithTokenProvider(&testTokenProvider{token: "fresh-token"})
+
+	var out map[string]string
+	res, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, &out)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal("Hello World!", out["msg"])
+	assert.Equal(2, calls)
+
+	token, _ := tokenCacher.Get(context.Background())
+	assert.Equal("fresh-token", token)
+}
+
+func TestHTTPClient_request_unauthorized_once(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	res, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.IsType(&APIError{}, err)
+	assert.Equal(2, calls)
+}
+
+func TestHTTPClient_request_unauthorized_notReplayable(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	tokenCacher := tokencacher.NewDefault()
+	_ = tokenCacher.Set(context.Background(), "revoked-token", time.Now().Add(time.Hour))
+
+	athenaClient.WithTokenCacher(tokenCacher)
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := pw.Write([]byte("foo=bar"))
+		pw.CloseWithError(err)
+	}()
+
+	res, err := athenaClient.request(context.Background(), "POST", "/", pr, nil, nil)
+
+	assert.NotNil(res)
+	assert.ErrorIs(err, ErrUnauthorized)
+	assert.Equal(1, calls)
+
+	_, err = tokenCacher.Get(context.Background())
+	assert.ErrorIs(err, tokencacher.ErrTokenNotExist)
+}
+
+func TestHTTPClient_request_unauthorized_attempts(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusUnauthorized)
+		case 2, 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"msg":"Hello World!"}`))
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	var attempts []int
+
+	athenaClient.WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (*http.Response, error) {
+			attempts = append(attempts, req.Attempt)
+
+			return next(ctx, req)
+		}
+	})
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	// The reauthenticated attempt is counted but does not use up the retry policy's 3 attempts.
+	assert.NoError(err)
+	assert.Equal([]int{1, 2, 3, 4}, attempts)
+	assert.Equal(4, calls)
+}
+
+type failingInvalidateTokenCacher struct {
+	testTokenCacher
+}
+
+func (f *failingInvalidateTokenCacher) Invalidate(context.Context) error {
+	return errors.New("invalidate failed")
+}
+
+func TestHTTPClient_request_unauthorized_invalidateError(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	athenaClient.WithTokenCacher(&failingInvalidateTokenCacher{})
+
+	res, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.ErrorIs(err, ErrUnauthorized)
+	assert.ErrorContains(err, "invalidate failed")
+}
+
 func TestHTTPClient_request_default_context_timeout(t *testing.T) {
 	assert := assert.New(t)
 
@@ -281,6 +465,42 @@ func TestHTTPClient_rate_limit(t *testing.T) {
 	assert.True(called)
 }
 
+func TestHTTPClient_rate_limit_route(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := &testRateLimiter{}
+
+	athenaClient, ts := testClient(nil)
+	athenaClient.WithRateLimiter(rateLimiter)
+
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), "GET", "/appointments/open?departmentid=1", nil, nil, nil)
+	assert.NoError(err)
+
+	route, ok := ratelimiter.RouteFromContext(rateLimiter.lastContext())
+	assert.True(ok)
+	assert.Equal(ratelimiter.Route{Method: "GET", Path: "/appointments/open"}, route)
+}
+
+func TestHTTPClient_rate_limit_observer(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":"Too Many Requests"}`))
+	})
+	defer ts.Close()
+
+	rateLimiter := ratelimiter.NewAdaptive(4, 100)
+	athenaClient.WithRateLimiter(rateLimiter)
+
+	_, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, nil)
+	assert.ErrorIs(err, ErrRateLimited)
+
+	assert.Equal(float64(2), rateLimiter.Rate(true))
+}
+
 func TestHTTPClient_WithPreview(t *testing.T) {
 	assert := assert.New(t)
 
@@ -291,6 +511,46 @@ func TestHTTPClient_WithPreview(t *testing.T) {
 	assert.False(athenaClient.preview)
 }
 
+func TestHTTPClient_WithBaseURL(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient := NewHTTPClient(&http.Client{}, "123", "", "")
+
+	athenaClient.WithBaseURL("http://localhost:8080/v1/")
+	assert.Equal("http://localhost:8080/v1/123", athenaClient.baseURL)
+
+	// The custom base URL is kept when switching environments.
+	athenaClient.WithPreview(false)
+	assert.Equal("http://localhost:8080/v1/123", athenaClient.baseURL)
+}
+
+func TestHTTPClient_Identity(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient := NewHTTPClient(&http.Client{}, "195900", "client", "secret")
+
+	assert.Equal(identity.Identity{ClientID: "client", PracticeID: "195900", Preview: true}, athenaClient.Identity())
+
+	athenaClient.WithPreview(false)
+
+	assert.False(athenaClient.Identity().Preview)
+}
+
+func TestHTTPClient_WithCredentialsSource(t *testing.T) {
+	assert := assert.New(t)
+
+	source := tokenprovider.NewStaticCredentials("rotated-client", "rotated-secret")
+
+	athenaClient := NewHTTPClient(&http.Client{}, "", "client", "secret").
+		WithCredentialsSource(source).
+		WithPreview(false)
+
+	// The source survives WithPreview.
+	provider, ok := athenaClient.tokenProvider.(*tokenprovider.Default)
+	assert.True(ok)
+	assert.Equal(tokenprovider.NewDefaultWithCredentials(&http.Client{}, source, false), provider)
+}
+
 func TestHTTPClient_WithTokenProvider(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,49 @@
+// Package identity identifies the athenahealth credentials, practice and environment an
+// HTTPClient uses, so state shared between clients (e.g. cached tokens and rate limits in Redis)
+// can be kept apart.
+package identity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	EnvironmentPreview = "preview"
+	EnvironmentProd    = "prod"
+
+	// clientIDHashLength is the number of hex characters of the client ID hash used in namespaces.
+	clientIDHashLength = 16
+)
+
+// Identity is the API client ID, practice ID and environment of an HTTPClient.
+type Identity struct {
+	ClientID   string
+	PracticeID string
+	Preview    bool
+}
+
+// Environment returns EnvironmentPreview or EnvironmentProd.
+func (i Identity) Environment() string {
+	if i.Preview {
+		return EnvironmentPreview
+	}
+
+	return EnvironmentProd
+}
+
+// ClientNamespace returns a string identifying only the client ID, e.g. 5d41402abc4b2a76. Use it
+// for state that athenahealth scopes to the API key rather than the practice, such as rate limits.
+// The client ID is hashed so it does not appear in keys.
+func (i Identity) ClientNamespace() string {
+	sum := sha256.Sum256([]byte(i.ClientID))
+
+	return hex.EncodeToString(sum[:])[:clientIDHashLength]
+}
+
+// Namespace returns a string identifying the client ID and practice ID, e.g.
+// 5d41402abc4b2a76:195900. The client ID is hashed so it does not appear in keys.
+func (i Identity) Namespace() string {
+	return fmt.Sprintf("%s:%s", i.ClientNamespace(), i.PracticeID)
+}
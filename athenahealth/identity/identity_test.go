@@ -0,0 +1,36 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentity_Environment(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(EnvironmentPreview, Identity{Preview: true}.Environment())
+	assert.Equal(EnvironmentProd, Identity{}.Environment())
+}
+
+func TestIdentity_ClientNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	i := Identity{ClientID: "client", PracticeID: "195900"}
+
+	assert.Equal("948fe603f61dc036", i.ClientNamespace())
+	assert.Equal(i.ClientNamespace(), Identity{ClientID: "client", PracticeID: "1"}.ClientNamespace())
+	assert.NotEqual(i.ClientNamespace(), Identity{ClientID: "other", PracticeID: "195900"}.ClientNamespace())
+}
+
+func TestIdentity_Namespace(t *testing.T) {
+	assert := assert.New(t)
+
+	i := Identity{ClientID: "client", PracticeID: "195900"}
+
+	assert.Equal("948fe603f61dc036:195900", i.Namespace())
+	assert.NotContains(i.Namespace(), "client")
+
+	assert.NotEqual(i.Namespace(), Identity{ClientID: "other", PracticeID: "195900"}.Namespace())
+	assert.NotEqual(i.Namespace(), Identity{ClientID: "client", PracticeID: "1"}.Namespace())
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -255,6 +256,21 @@ func (h *HTTPClient) ListPatientInsurancePackages(ctx context.Context, opts *Lis
 	}, nil
 }
 
+// AllPatientInsurancePackages iterates over the patient's insurance packages, calling
+// ListPatientInsurancePackages for each page as needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllPatientInsurancePackages(ctx context.Context, opts *ListPatientInsurancePackagesOptions) iter.Seq2[*InsurancePackage, error] {
+	pagination := func(o *ListPatientInsurancePackagesOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListPatientInsurancePackagesOptions) ([]*InsurancePackage, *PaginationResult, error) {
+		res, err := h.ListPatientInsurancePackages(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.InsurancePackages, res.Pagination, nil
+	})
+}
+
 type UploadPatientInsuranceCardImageOptions struct {
 	DepartmentID string
 	Image        []byte
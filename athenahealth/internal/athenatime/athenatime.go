@@ -0,0 +1,17 @@
+// Package athenatime holds how athenahealth interprets timestamps, so the change feed poller and
+// the fake server in athenatest agree on it.
+package athenatime
+
+import "time"
+
+// Location returns the time zone athenahealth uses for timestamps without an offset, such as the
+// processed datetimes of changed-record endpoints. It falls back to time.Local if the time zone
+// database is unavailable.
+func Location() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.Local
+	}
+
+	return location
+}
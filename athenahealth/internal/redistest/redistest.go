@@ -0,0 +1,22 @@
+// Package redistest provides helpers for testing Redis-backed implementations against miniredis.
+package redistest
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewClusterClient returns a Redis Cluster client whose only node is addr, so cluster mode can be
+// tested against miniredis, which does not implement the CLUSTER commands.
+func NewClusterClient(addr string) *redis.ClusterClient {
+	return redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{{
+				Start: 0,
+				End:   16383,
+				Nodes: []redis.ClusterNode{{Addr: addr}},
+			}}, nil
+		},
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -117,6 +118,21 @@ func (h *HTTPClient) ListLabResults(ctx context.Context, patientID string, depar
 	}, nil
 }
 
+// AllLabResults iterates over the patient's lab results, calling ListLabResults for each page as
+// needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllLabResults(ctx context.Context, patientID string, departmentID string, opts *ListLabResultsOptions) iter.Seq2[*LabResult, error] {
+	pagination := func(o *ListLabResultsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListLabResultsOptions) ([]*LabResult, *PaginationResult, error) {
+		res, err := h.ListLabResults(ctx, patientID, departmentID, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.LabResults, res.Pagination, nil
+	})
+}
+
 type LabResultAttachmentType string
 
 const (
@@ -227,13 +243,13 @@ func (h *HTTPClient) AddLabResultDocumentReader(ctx context.Context, patientID s
 
 	out := &addLabResultDocumentResponse{}
 
-	_, err := h.PostFormReader(ctx, fmt.Sprintf("patients/%s/documents/labresult", patientID), form, out)
+	res, err := h.PostFormReader(ctx, fmt.Sprintf("patients/%s/documents/labresult", patientID), form, out)
 	if err != nil {
 		return 0, err
 	}
 
 	if !out.Success {
-		return 0, errors.New(out.ErrorMessage)
+		return 0, fmt.Errorf("adding lab result document: %w", envelopeError(res))
 	}
 
 	return out.LabResultID, nil
@@ -312,13 +328,13 @@ func (h *HTTPClient) AddLabResultDocument(ctx context.Context, patientID string,
 
 	out := &addLabResultDocumentResponse{}
 
-	_, err := h.PostForm(ctx, fmt.Sprintf("patients/%s/documents/labresult", patientID), form, out)
+	res, err := h.PostForm(ctx, fmt.Sprintf("patients/%s/documents/labresult", patientID), form, out)
 	if err != nil {
 		return 0, err
 	}
 
 	if !out.Success {
-		return 0, errors.New(out.ErrorMessage)
+		return 0, fmt.Errorf("adding lab result document: %w", envelopeError(res))
 	}
 
 	return out.LabResultID, nil
@@ -432,3 +448,18 @@ func (h *HTTPClient) ListChangedLabResults(ctx context.Context, opts *ListChange
 		Pagination:        makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// AllChangedLabResults iterates over changed lab results, calling ListChangedLabResults for each
+// page as needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllChangedLabResults(ctx context.Context, opts *ListChangedLabResultsOptions) iter.Seq2[*ChangedLabResult, error] {
+	pagination := func(o *ListChangedLabResultsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListChangedLabResultsOptions) ([]*ChangedLabResult, *PaginationResult, error) {
+		res, err := h.ListChangedLabResults(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.ChangedLabResults, res.Pagination, nil
+	})
+}
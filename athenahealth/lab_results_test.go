@@ -115,6 +115,48 @@ func TestHTTPClient_AddLabResultDocument(t *testing.T) {
 	assert.Equal(res, 1083563)
 }
 
+func TestHTTPClient_AddLabResultDocument_unsuccessful(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":false,"errormessage":"Invalid attachment type."}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	res, err := athenaClient.AddLabResultDocument(context.Background(), "123", "456", &AddLabResultDocumentOptions{})
+	assert.Zero(res)
+	assert.ErrorIs(err, ErrValidation)
+
+	apiErr := &APIError{}
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal("Invalid attachment type.", apiErr.AthenaError)
+	assert.NotEmpty(apiErr.XRequestID)
+}
+
+func TestHTTPClient_AddLabResultDocumentReader_unsuccessful(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":false,"errormessage":"Invalid attachment type."}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	res, err := athenaClient.AddLabResultDocumentReader(context.Background(), "123", "456", &AddLabResultDocumentReaderOptions{
+		AttachmentContents: bytes.NewReader([]byte(`test bytes`)),
+	})
+	assert.Zero(res)
+	assert.ErrorIs(err, ErrValidation)
+
+	apiErr := &APIError{}
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal("Invalid attachment type.", apiErr.AthenaError)
+	assert.NotEmpty(apiErr.XRequestID)
+}
+
 func TestHTTPClient_AddLabResultDocument_observation_without_time(t *testing.T) {
 	assert := assert.New(t)
 
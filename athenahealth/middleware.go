@@ -0,0 +1,49 @@
+package athenahealth
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Request is a single attempt of an athenahealth API request, as seen by Middleware.
+type Request struct {
+	Method string
+	// Path is the athenahealth API path relative to the practice, including the query string
+	// (e.g. /patients/1?showinsurance=true).
+	Path string
+	URL  string
+	// Header contains the headers that will be sent, including Authorization and X-Request-Id.
+	// Middleware may modify it before calling the next handler.
+	Header http.Header
+	// Body is read at most once. It is nil for requests without a body.
+	Body io.Reader
+	// Attempt is 1 for the first attempt and is incremented for each retry.
+	Attempt int
+}
+
+// RequestHandler performs a Request. A non-2xx response is returned along with an *APIError.
+type RequestHandler func(ctx context.Context, req *Request) (*http.Response, error)
+
+// Middleware wraps a RequestHandler. It may inspect or modify the Request before calling next,
+// inspect the response and error returned by next, or short-circuit by returning its own
+// response without calling next at all.
+type Middleware func(next RequestHandler) RequestHandler
+
+// WithMiddleware appends middleware to the client's middleware chain. Middleware is called in
+// the order it was added, so the first one added is the outermost.
+func (h *HTTPClient) WithMiddleware(middleware ...Middleware) *HTTPClient {
+	h.middleware = append(h.middleware, middleware...)
+
+	return h
+}
+
+func (h *HTTPClient) handler() RequestHandler {
+	handler := h.send
+
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handler = h.middleware[i](handler)
+	}
+
+	return handler
+}
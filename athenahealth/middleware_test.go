@@ -0,0 +1,160 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient_WithMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("bar", r.Header.Get("X-Foo"))
+
+		_, _ = w.Write([]byte(`{"msg":"Hello World!"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var calls []string
+
+	athenaClient.WithMiddleware(
+		func(next RequestHandler) RequestHandler {
+			return func(ctx context.Context, req *Request) (*http.Response, error) {
+				calls = append(calls, "first")
+
+				assert.Equal(http.MethodGet, req.Method)
+				assert.Equal("/patients/1?foo=bar", req.Path)
+				assert.Equal(1, req.Attempt)
+				assert.NotEmpty(req.Header.Get(XRequestIDHeaderKey))
+
+				req.Header.Set("X-Foo", "bar")
+
+				return next(ctx, req)
+			}
+		},
+		func(next RequestHandler) RequestHandler {
+			return func(ctx context.Context, req *Request) (*http.Response, error) {
+				calls = append(calls, "second")
+
+				res, err := next(ctx, req)
+				assert.NoError(err)
+				assert.Equal(http.StatusOK, res.StatusCode)
+
+				return res, err
+			}
+		},
+	)
+
+	var out map[string]string
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "patients/1?foo=bar", nil, nil, &out)
+
+	assert.NoError(err)
+	assert.Equal("Hello World!", out["msg"])
+	assert.Equal([]string{"first", "second"}, calls)
+}
+
+func TestHTTPClient_WithMiddleware_APIError(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not found"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	var middlewareErr error
+
+	athenaClient.WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (*http.Response, error) {
+			res, err := next(ctx, req)
+			middlewareErr = err
+
+			return res, err
+		}
+	})
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	apiErr := &APIError{}
+	assert.True(errors.As(middlewareErr, &apiErr))
+	assert.Equal("not found", apiErr.AthenaError)
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+func TestHTTPClient_WithMiddleware_shortCircuit(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	h := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	status := http.StatusOK
+
+	athenaClient.WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewBufferString(`{"msg":"short-circuited"}`)),
+			}, nil
+		}
+	})
+
+	var out map[string]string
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, &out)
+
+	assert.NoError(err)
+	assert.Equal("short-circuited", out["msg"])
+	assert.False(called)
+
+	status = http.StatusServiceUnavailable
+
+	_, err = athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.IsType(&APIError{}, err)
+	assert.False(called)
+}
+
+func TestHTTPClient_WithMiddleware_attempt(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	var attempts []int
+	var requestIDs []string
+
+	athenaClient.WithMiddleware(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (*http.Response, error) {
+			attempts = append(attempts, req.Attempt)
+			requestIDs = append(requestIDs, req.Header.Get(XRequestIDHeaderKey))
+
+			return next(ctx, req)
+		}
+	})
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.Error(err)
+	assert.Equal([]int{1, 2, 3}, attempts)
+	assert.NotEqual(requestIDs[0], requestIDs[1])
+}
@@ -0,0 +1,74 @@
+package athenahealth
+
+import (
+	"context"
+	"iter"
+)
+
+// pageFunc fetches the page of items described by pagination.
+type pageFunc[T any] func(ctx context.Context, pagination *PaginationOptions) ([]T, *PaginationResult, error)
+
+// paginate returns an iterator over the items of every page returned by fetch, starting at
+// pagination.Offset and requesting pagination.Limit items per page. Iteration stops after
+// pagination.MaxItems items, when there is no next page, or when the caller breaks out of the
+// loop. A fetch error is yielded once and ends iteration.
+func paginate[T any](ctx context.Context, pagination *PaginationOptions, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var p PaginationOptions
+		if pagination != nil {
+			p = *pagination
+		}
+
+		yielded := 0
+
+		for {
+			items, result, err := fetch(ctx, &PaginationOptions{
+				Limit:  p.Limit,
+				Offset: p.Offset,
+			})
+			if err != nil {
+				var zero T
+				yield(zero, err)
+
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+
+				yielded++
+				if p.MaxItems > 0 && yielded >= p.MaxItems {
+					return
+				}
+			}
+
+			if len(items) == 0 || result == nil || result.NextOffset <= p.Offset {
+				return
+			}
+
+			p.Offset = result.NextOffset
+		}
+	}
+}
+
+// paginateList returns an iterator over the items of every page returned by list, for List methods
+// whose options hold their PaginationOptions in a field. pagination returns a pointer to that
+// field; each page is fetched with a copy of opts whose field is set to the page's options.
+func paginateList[O, T any](ctx context.Context, opts *O, pagination func(*O) **PaginationOptions, list func(context.Context, *O) ([]T, *PaginationResult, error)) iter.Seq2[T, error] {
+	var p *PaginationOptions
+	if opts != nil {
+		p = *pagination(opts)
+	}
+
+	return paginate(ctx, p, func(ctx context.Context, page *PaginationOptions) ([]T, *PaginationResult, error) {
+		pageOpts := new(O)
+		if opts != nil {
+			*pageOpts = *opts
+		}
+		*pagination(pageOpts) = page
+
+		return list(ctx, pageOpts)
+	})
+}
@@ -0,0 +1,115 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPages returns a pageFunc serving total items in pages and recording the requested offsets.
+func testPages(total int, offsets *[]int) pageFunc[int] {
+	return func(ctx context.Context, p *PaginationOptions) ([]int, *PaginationResult, error) {
+		*offsets = append(*offsets, p.Offset)
+
+		limit := p.Limit
+		if limit == 0 {
+			limit = 10
+		}
+
+		var items []int
+		for i := p.Offset; i < total && i < p.Offset+limit; i++ {
+			items = append(items, i)
+		}
+
+		next := 0
+		if p.Offset+limit < total {
+			next = p.Offset + limit
+		}
+
+		return items, makePaginationResult(fmt.Sprintf("/foo?offset=%d", next), "", total), nil
+	}
+}
+
+func Test_paginate(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+	var items []int
+
+	for item, err := range paginate(context.Background(), &PaginationOptions{Limit: 3}, testPages(8, &offsets)) {
+		assert.NoError(err)
+		items = append(items, item)
+	}
+
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7}, items)
+	assert.Equal([]int{0, 3, 6}, offsets)
+}
+
+func Test_paginate_offsetAndMaxItems(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+	var items []int
+
+	for item, err := range paginate(context.Background(), &PaginationOptions{Limit: 3, Offset: 2, MaxItems: 4}, testPages(20, &offsets)) {
+		assert.NoError(err)
+		items = append(items, item)
+	}
+
+	assert.Equal([]int{2, 3, 4, 5}, items)
+	assert.Equal([]int{2, 5}, offsets)
+}
+
+func Test_paginate_break(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+	var items []int
+
+	for item := range paginate(context.Background(), nil, testPages(100, &offsets)) {
+		if item == 12 {
+			break
+		}
+
+		items = append(items, item)
+	}
+
+	assert.Len(items, 12)
+	assert.Equal([]int{0, 10}, offsets)
+}
+
+func Test_paginate_error(t *testing.T) {
+	assert := assert.New(t)
+
+	fetchErr := errors.New("fetch error")
+
+	calls := 0
+	fetch := func(ctx context.Context, p *PaginationOptions) ([]int, *PaginationResult, error) {
+		calls++
+
+		if p.Offset > 0 {
+			return nil, nil, fetchErr
+		}
+
+		return []int{0, 1}, &PaginationResult{NextOffset: 2}, nil
+	}
+
+	var items []int
+	var errs []error
+
+	for item, err := range paginate(context.Background(), nil, fetch) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	assert.Equal([]int{0, 1}, items)
+	assert.Equal([]error{fetchErr}, errs)
+	assert.Equal(2, calls)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/url"
 	"strconv"
 	"strings"
@@ -408,6 +409,22 @@ func (h *HTTPClient) ListPatients(ctx context.Context, opts *ListPatientsOptions
 	}, nil
 }
 
+// AllPatients iterates over the patients matching opts, calling ListPatients for each page as
+// needed. Iteration starts at opts.Pagination.Offset and stops after opts.Pagination.MaxItems
+// patients if set.
+func (h *HTTPClient) AllPatients(ctx context.Context, opts *ListPatientsOptions) iter.Seq2[*Patient, error] {
+	pagination := func(o *ListPatientsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListPatientsOptions) ([]*Patient, *PaginationResult, error) {
+		res, err := h.ListPatients(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Patients, res.Pagination, nil
+	})
+}
+
 type (
 	UpdatePatientOptions struct {
 		Address1            *string
@@ -960,6 +977,22 @@ func (h *HTTPClient) ListPatientsMatchingCustomField(ctx context.Context, opts *
 	}, nil
 }
 
+// AllPatientsMatchingCustomField iterates over the patients whose custom field matches opts,
+// calling ListPatientsMatchingCustomField for each page as needed. Set opts.Pagination.MaxItems to
+// stop early.
+func (h *HTTPClient) AllPatientsMatchingCustomField(ctx context.Context, opts *ListPatientsMatchingCustomFieldOptions) iter.Seq2[*Patient, error] {
+	pagination := func(o *ListPatientsMatchingCustomFieldOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListPatientsMatchingCustomFieldOptions) ([]*Patient, *PaginationResult, error) {
+		res, err := h.ListPatientsMatchingCustomField(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Patients, res.Pagination, nil
+	})
+}
+
 type CreatePatientOptions struct {
 	Address1              string
 	Address2              string
@@ -1022,7 +1055,7 @@ func (h *HTTPClient) CreatePatient(ctx context.Context, opts *CreatePatientOptio
 		form.Add("bypasspatientmatching", "true")
 	}
 
-	_, err := h.PostForm(ctx, "/patients", form, &out)
+	res, err := h.PostForm(ctx, "/patients", form, &out)
 	if err != nil {
 		return "", err
 	}
@@ -1031,8 +1064,9 @@ func (h *HTTPClient) CreatePatient(ctx context.Context, opts *CreatePatientOptio
 		return "", errors.New("unexpected response")
 	}
 
-	if len(out[0].ErrorMessage) > 0 {
-		return "", errors.New(out[0].ErrorMessage)
+	err = checkEnvelope(res)
+	if err != nil {
+		return "", err
 	}
 
 	return out[0].PatientID, nil
@@ -102,6 +102,44 @@ func TestHTTPClient_ListPatients(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestHTTPClient_AllPatients(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Smith", r.URL.Query().Get("lastname"))
+		assert.Equal("2", r.URL.Query().Get("limit"))
+
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+
+		next := "/patients?limit=2&offset=2"
+		if r.URL.Query().Get("offset") == "2" {
+			next = ""
+		}
+
+		_, _ = w.Write([]byte(`{"next":"` + next + `","patients":[{"patientid":"1"},{"patientid":"2"}]}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	opts := &ListPatientsOptions{
+		LastName:   "Smith",
+		Pagination: &PaginationOptions{Limit: 2},
+	}
+
+	var patientIDs []string
+
+	for patient, err := range athenaClient.AllPatients(context.Background(), opts) {
+		assert.NoError(err)
+		patientIDs = append(patientIDs, patient.PatientID)
+	}
+
+	assert.Equal([]string{"1", "2", "1", "2"}, patientIDs)
+	assert.Equal([]string{"", "2"}, offsets)
+	assert.Zero(opts.Pagination.Offset)
+}
+
 func TestHTTPClient_GetPatientPhoto_JPEGOutputNotSupported(t *testing.T) {
 	assert := assert.New(t)
 
@@ -412,6 +450,26 @@ func TestHTTPClient_CreatePatient(t *testing.T) {
 	assert.Equal("100", actualPatientID)
 }
 
+func TestHTTPClient_CreatePatient_duplicate(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"errormessage":"A duplicate patient already exists."}]`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	patientID, err := athenaClient.CreatePatient(context.Background(), &CreatePatientOptions{})
+	assert.Empty(patientID)
+	assert.ErrorIs(err, ErrConflict)
+
+	apiErr := &APIError{}
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal("A duplicate patient already exists.", apiErr.AthenaError)
+	assert.NotEmpty(apiErr.XRequestID)
+}
+
 func TestHTTPClient_UpdatePatient(t *testing.T) {
 	assert := assert.New(t)
 
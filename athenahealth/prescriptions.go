@@ -3,6 +3,7 @@ package athenahealth
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -94,6 +95,21 @@ func (h *HTTPClient) ListChangedPrescriptions(ctx context.Context, opts *ListCha
 	}, nil
 }
 
+// AllChangedPrescriptions iterates over changed prescriptions, calling ListChangedPrescriptions for
+// each page as needed. Set opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllChangedPrescriptions(ctx context.Context, opts *ListChangedPrescriptionsOptions) iter.Seq2[*ChangedPrescription, error] {
+	pagination := func(o *ListChangedPrescriptionsOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListChangedPrescriptionsOptions) ([]*ChangedPrescription, *PaginationResult, error) {
+		res, err := h.ListChangedPrescriptions(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.ChangedPrescriptions, res.Pagination, nil
+	})
+}
+
 type UpdatePrescriptionOptions struct {
 	ActionNote   *string `json:"actionnote"`
 	AssignedTo   *string `json:"assignedto"`
@@ -137,7 +153,8 @@ func (h *HTTPClient) UpdatePrescription(ctx context.Context, departmentID int, p
 		}
 	}
 
-	if _, err := h.PutForm(ctx, fmt.Sprintf("/patients/%d/documents/prescriptions/%d", patientID, prescriptionID), form, out); err != nil {
+	res, err := h.PutForm(ctx, fmt.Sprintf("/patients/%d/documents/prescriptions/%d", patientID, prescriptionID), form, out)
+	if err != nil {
 		return &UpdatePrescriptionResult{
 			Success:      false,
 			ErrorMessage: fmt.Errorf("updating prescription: %w", err).Error(),
@@ -145,11 +162,7 @@ func (h *HTTPClient) UpdatePrescription(ctx context.Context, departmentID int, p
 	}
 
 	if !out.Success {
-		if out.ErrorMessage != "" {
-			return out, fmt.Errorf("updating prescription: %s", out.ErrorMessage)
-		}
-
-		return out, fmt.Errorf("unexpected response from athena")
+		return out, fmt.Errorf("updating prescription: %w", envelopeError(res))
 	}
 
 	return out, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -149,3 +150,18 @@ func (h *HTTPClient) ListProviders(ctx context.Context, opts *ListProvidersOptio
 		Pagination: makePaginationResult(out.Next, out.Previous, out.TotalCount),
 	}, nil
 }
+
+// AllProviders iterates over providers, calling ListProviders for each page as needed. Set
+// opts.Pagination.MaxItems to stop early.
+func (h *HTTPClient) AllProviders(ctx context.Context, opts *ListProvidersOptions) iter.Seq2[*Provider, error] {
+	pagination := func(o *ListProvidersOptions) **PaginationOptions { return &o.Pagination }
+
+	return paginateList(ctx, opts, pagination, func(ctx context.Context, pageOpts *ListProvidersOptions) ([]*Provider, *PaginationResult, error) {
+		res, err := h.ListProviders(ctx, pageOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res.Providers, res.Pagination, nil
+	})
+}
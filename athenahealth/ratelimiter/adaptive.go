@@ -0,0 +1,215 @@
+package ratelimiter
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAdaptiveIncrease = 0.1
+	defaultAdaptiveDecrease = 0.5
+	defaultAdaptiveCooldown = time.Second
+
+	// QPSAllottedHeader is the header in which athenahealth's API gateway reports the requests per
+	// second allotted to the API key.
+	QPSAllottedHeader = "X-Plan-QPS-Allotted"
+)
+
+// Adaptive is an in-memory RateLimiter that adjusts its rate to athenahealth's responses with
+// additive increase/multiplicative decrease (AIMD). Every 429 response multiplies the rate by the
+// decrease factor (once per cooldown, so a burst of 429s only counts once) and pauses requests for
+// the response's Retry-After delay. Every other response adds the increase to the rate, up to the
+// maximum rate. An allotted rate reported in the QPSAllottedHeader header lowers the maximum.
+//
+// HTTPClient reports responses to Adaptive through the RateLimitObserver interface. Adaptive is
+// safe for concurrent use.
+type Adaptive struct {
+	preview *adaptiveLimit
+	prod    *adaptiveLimit
+
+	minRate  float64
+	increase float64
+	decrease float64
+	cooldown time.Duration
+
+	now  func() time.Time
+	lock sync.Mutex
+}
+
+type adaptiveLimit struct {
+	bucket *bucket
+
+	maxRate      float64
+	allotted     float64
+	lastDecrease time.Time
+	pausedUntil  time.Time
+}
+
+// NewAdaptive returns an Adaptive rate limiter that starts at, and never exceeds, ratePreview and
+// rateProd requests per second. Rates default to the same values as NewRedis.
+func NewAdaptive(ratePreview, rateProd float64) *Adaptive {
+	if ratePreview <= 0 {
+		ratePreview = defaultRatePerSecPreview
+	}
+
+	if rateProd <= 0 {
+		rateProd = defaultRatePerSecProd
+	}
+
+	return &Adaptive{
+		preview: newAdaptiveLimit(ratePreview),
+		prod:    newAdaptiveLimit(rateProd),
+
+		minRate:  math.Min(1, math.Min(ratePreview, rateProd)),
+		increase: defaultAdaptiveIncrease,
+		decrease: defaultAdaptiveDecrease,
+		cooldown: defaultAdaptiveCooldown,
+
+		now: time.Now,
+	}
+}
+
+func newAdaptiveLimit(rate float64) *adaptiveLimit {
+	return &adaptiveLimit{
+		// A burst of 1 keeps requests evenly spaced, so a lowered rate takes effect immediately.
+		bucket:  newBucket(rate, 1),
+		maxRate: rate,
+	}
+}
+
+// WithMinRate sets the rate, in requests per second, below which 429s no longer lower the rate.
+// It defaults to 1.
+func (a *Adaptive) WithMinRate(minRate float64) *Adaptive {
+	a.minRate = minRate
+
+	return a
+}
+
+// WithAIMD sets the rate added after every successful response (default 0.1 requests per second)
+// and the factor the rate is multiplied by after a 429 (default 0.5).
+func (a *Adaptive) WithAIMD(increase, decrease float64) *Adaptive {
+	if decrease <= 0 || decrease >= 1 {
+		panic("decrease must be between 0 and 1")
+	}
+
+	a.increase = increase
+	a.decrease = decrease
+
+	return a
+}
+
+// WithCooldown sets the minimum time between two decreases. It defaults to 1 second.
+func (a *Adaptive) WithCooldown(cooldown time.Duration) *Adaptive {
+	a.cooldown = cooldown
+
+	return a
+}
+
+// Rate returns the current rate, in requests per second, of an environment.
+func (a *Adaptive) Rate(preview bool) float64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.limit(preview).bucket.rate
+}
+
+func (a *Adaptive) limit(preview bool) *adaptiveLimit {
+	if preview {
+		return a.preview
+	}
+
+	return a.prod
+}
+
+func (a *Adaptive) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+	l := a.limit(preview)
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now), ErrRateExceeded
+	}
+
+	l.bucket.refill(now)
+
+	retryAfter := l.bucket.wait()
+	if retryAfter > 0 {
+		return retryAfter, ErrRateExceeded
+	}
+
+	l.bucket.tokens--
+
+	return 0, nil
+}
+
+// Observe adjusts the rate of an environment to a response.
+func (a *Adaptive) Observe(ctx context.Context, preview bool, res *http.Response) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+	l := a.limit(preview)
+
+	if allotted, err := strconv.ParseFloat(strings.TrimSpace(res.Header.Get(QPSAllottedHeader)), 64); err == nil && allotted > 0 {
+		l.allotted = allotted
+	}
+
+	ceiling := l.maxRate
+	if l.allotted > 0 {
+		ceiling = math.Min(ceiling, l.allotted)
+	}
+
+	// Refill at the old rate before changing it.
+	l.bucket.refill(now)
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		l.bucket.rate = math.Min(ceiling, l.bucket.rate+a.increase)
+
+		return
+	}
+
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), now); ok {
+		l.pausedUntil = maxTime(l.pausedUntil, now.Add(retryAfter))
+	}
+
+	if !l.lastDecrease.IsZero() && now.Sub(l.lastDecrease) < a.cooldown {
+		return
+	}
+
+	l.lastDecrease = now
+	l.bucket.rate = math.Min(ceiling, math.Max(a.minRate, l.bucket.rate*a.decrease))
+	l.bucket.tokens = math.Min(l.bucket.tokens, 0)
+}
+
+// parseRetryAfter parses a Retry-After header value in either delay-seconds or HTTP-date form.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now), true
+	}
+
+	return 0, false
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
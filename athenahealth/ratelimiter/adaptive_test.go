@@ -0,0 +1,134 @@
+package ratelimiter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAdaptive(ratePreview, rateProd float64) (*Adaptive, *time.Time) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	a := NewAdaptive(ratePreview, rateProd)
+	a.now = func() time.Time {
+		return now
+	}
+
+	return a, &now
+}
+
+func response(statusCode int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{StatusCode: statusCode, Header: header}
+}
+
+func TestAdaptive_Allowed(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, now := newTestAdaptive(4, 100)
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Equal(250*time.Millisecond, retryAfter)
+
+	_, err = rateLimiter.Allowed(context.Background(), false)
+	assert.NoError(err)
+
+	*now = now.Add(retryAfter)
+
+	_, err = rateLimiter.Allowed(context.Background(), true)
+	assert.NoError(err)
+}
+
+func TestAdaptive_Observe(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, now := newTestAdaptive(4, 100)
+	rateLimiter.WithAIMD(1, 0.5).WithCooldown(time.Second)
+
+	rateLimiter.Observe(context.Background(), true, response(http.StatusTooManyRequests, nil))
+	assert.Equal(float64(2), rateLimiter.Rate(true))
+	assert.Equal(float64(100), rateLimiter.Rate(false))
+
+	// 429s within the cooldown only count once.
+	rateLimiter.Observe(context.Background(), true, response(http.StatusTooManyRequests, nil))
+	assert.Equal(float64(2), rateLimiter.Rate(true))
+
+	*now = now.Add(time.Second)
+
+	rateLimiter.Observe(context.Background(), true, response(http.StatusTooManyRequests, nil))
+	assert.Equal(float64(1), rateLimiter.Rate(true))
+
+	// The rate does not go below the minimum.
+	*now = now.Add(time.Second)
+
+	rateLimiter.Observe(context.Background(), true, response(http.StatusTooManyRequests, nil))
+	assert.Equal(float64(1), rateLimiter.Rate(true))
+
+	// Successful responses recover the rate up to the maximum.
+	for range 5 {
+		rateLimiter.Observe(context.Background(), true, response(http.StatusOK, nil))
+	}
+
+	assert.Equal(float64(4), rateLimiter.Rate(true))
+}
+
+func TestAdaptive_Observe_retryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, now := newTestAdaptive(100, 100)
+
+	header := http.Header{}
+	header.Set("Retry-After", "2")
+
+	rateLimiter.Observe(context.Background(), false, response(http.StatusTooManyRequests, header))
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), false)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Equal(2*time.Second, retryAfter)
+
+	*now = now.Add(2 * time.Second)
+
+	_, err = rateLimiter.Allowed(context.Background(), false)
+	assert.NoError(err)
+}
+
+func TestAdaptive_Observe_allotted(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, _ := newTestAdaptive(5, 100)
+
+	header := http.Header{}
+	header.Set(QPSAllottedHeader, "20")
+
+	rateLimiter.Observe(context.Background(), false, response(http.StatusOK, header))
+	assert.Equal(float64(20), rateLimiter.Rate(false))
+
+	// The allotted rate is remembered.
+	rateLimiter.Observe(context.Background(), false, response(http.StatusOK, nil))
+	assert.Equal(float64(20), rateLimiter.Rate(false))
+
+	// An allotted rate above the maximum is ignored.
+	header.Set(QPSAllottedHeader, "10")
+
+	rateLimiter.Observe(context.Background(), true, response(http.StatusOK, header))
+	assert.Equal(float64(5), rateLimiter.Rate(true))
+}
+
+func TestAdaptive_WithAIMD_invalidDecrease(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewAdaptive(1, 1).WithAIMD(1, 1)
+	})
+}
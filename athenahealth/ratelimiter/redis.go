@@ -2,27 +2,31 @@ package ratelimiter
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
 	"github.com/go-redis/redis/v8"
 	"github.com/go-redis/redis_rate/v9"
 )
 
-const redisKeyPreview = "athena_rate_limit:preview"
-const redisKeyProd = "athena_rate_limit:prod"
+const redisKeyPrefix = "athena_rate_limit"
 
 const defaultRatePerSecPreview = 5
 const defaultRatePerSecProd = 100
 
 type Redis struct {
-	client  *redis.Client
+	client  redis.UniversalClient
 	limiter *redis_rate.Limiter
 
 	ratePreivew int
 	rateProd    int
+
+	keyPreview string
+	keyProd    string
 }
 
-func NewRedis(client *redis.Client, ratePreview, rateProd int) *Redis {
+func NewRedis(client redis.UniversalClient, ratePreview, rateProd int) *Redis {
 	if client == nil {
 		panic("client is nil")
 	}
@@ -41,20 +45,38 @@ func NewRedis(client *redis.Client, ratePreview, rateProd int) *Redis {
 
 		ratePreivew: ratePreview,
 		rateProd:    rateProd,
+
+		keyPreview: fmt.Sprintf("%s:%s", redisKeyPrefix, identity.EnvironmentPreview),
+		keyProd:    fmt.Sprintf("%s:%s", redisKeyPrefix, identity.EnvironmentProd),
 	}
 
 	return r
 }
 
+// NewRedisForIdentity returns a Redis rate limiter whose keys are namespaced by the client ID of id
+// (see HTTPClient.Identity), e.g. athena_rate_limit:5d41402abc4b2a76:prod, so clients with
+// different credentials can share a Redis. athenahealth enforces rate limits per API key, so the
+// practice ID is not part of the key and clients for different practices using the same
+// credentials share one limit. NewRedis keeps using the athena_rate_limit:preview and
+// athena_rate_limit:prod keys.
+func NewRedisForIdentity(client redis.UniversalClient, id identity.Identity, ratePreview, rateProd int) *Redis {
+	r := NewRedis(client, ratePreview, rateProd)
+
+	r.keyPreview = fmt.Sprintf("%s:%s:%s", redisKeyPrefix, id.ClientNamespace(), identity.EnvironmentPreview)
+	r.keyProd = fmt.Sprintf("%s:%s:%s", redisKeyPrefix, id.ClientNamespace(), identity.EnvironmentProd)
+
+	return r
+}
+
 func (r *Redis) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
 	var key string
 	var limit redis_rate.Limit
 
 	if preview {
-		key = redisKeyPreview
+		key = r.keyPreview
 		limit = redis_rate.PerSecond(r.ratePreivew)
 	} else {
-		key = redisKeyProd
+		key = r.keyProd
 		limit = redis_rate.PerSecond(r.rateProd)
 	}
 
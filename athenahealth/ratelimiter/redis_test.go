@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/redistest"
 	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 )
@@ -54,3 +56,87 @@ func TestRedis_Allowed(t *testing.T) {
 	assert.Zero(retryAfter)
 	assert.NoError(err)
 }
+
+func TestNewRedisForIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	a := NewRedisForIdentity(client, identity.Identity{ClientID: "a", PracticeID: "195900"}, 1, 1)
+	b := NewRedisForIdentity(client, identity.Identity{ClientID: "b", PracticeID: "195900"}, 1, 1)
+
+	_, err = a.Allowed(context.Background(), false)
+	assert.NoError(err)
+
+	_, err = a.Allowed(context.Background(), false)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	// b has its own limit.
+	_, err = b.Allowed(context.Background(), false)
+	assert.NoError(err)
+
+	assert.Equal("athena_rate_limit:ca978112ca1bbdca:prod", a.keyProd)
+	assert.Equal("athena_rate_limit:ca978112ca1bbdca:preview", a.keyPreview)
+
+	legacy := NewRedis(client, 1, 1)
+	assert.Equal("athena_rate_limit:prod", legacy.keyProd)
+	assert.Equal("athena_rate_limit:preview", legacy.keyPreview)
+}
+
+func TestNewRedisForIdentity_sharedAcrossPractices(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	a := NewRedisForIdentity(client, identity.Identity{ClientID: "a", PracticeID: "195900"}, 1, 1)
+	b := NewRedisForIdentity(client, identity.Identity{ClientID: "a", PracticeID: "1"}, 1, 1)
+
+	_, err = a.Allowed(context.Background(), false)
+	assert.NoError(err)
+
+	// b uses the same credentials, so it draws from a's bucket.
+	_, err = b.Allowed(context.Background(), false)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	assert.Equal(a.keyProd, b.keyProd)
+}
+
+func TestRedis_Allowed_cluster(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	rateLimiter := NewRedis(redistest.NewClusterClient(s.Addr()), 1, 1)
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.NotZero(retryAfter)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), false)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+}
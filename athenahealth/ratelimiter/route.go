@@ -0,0 +1,34 @@
+package ratelimiter
+
+import (
+	"context"
+	"strings"
+)
+
+// Route identifies the athenahealth endpoint a request is for. HTTPClient adds it to the context
+// passed to RateLimiter.Allowed so rate limiters can apply per-route limits.
+type Route struct {
+	Method string
+	// Path is the request path relative to the practice, without the query string, e.g.
+	// /appointments/open.
+	Path string
+}
+
+type routeContextKey struct{}
+
+// ContextWithRoute returns a copy of ctx carrying the route for method and path. Any query string
+// is removed from path.
+func ContextWithRoute(ctx context.Context, method, path string) context.Context {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	return context.WithValue(ctx, routeContextKey{}, Route{Method: method, Path: path})
+}
+
+// RouteFromContext returns the route carried by ctx, if any.
+func RouteFromContext(ctx context.Context) (Route, bool) {
+	route, ok := ctx.Value(routeContextKey{}).(Route)
+
+	return route, ok
+}
@@ -0,0 +1,161 @@
+package ratelimiter
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenBucket is an in-memory RateLimiter for single-instance deployments. Each environment has a
+// bucket that holds up to burst tokens and refills at a steady rate; every allowed request takes a
+// token. Routes that athenahealth throttles harder can be given additional buckets with
+// WithRouteLimit. TokenBucket is safe for concurrent use.
+type TokenBucket struct {
+	preview *bucket
+	prod    *bucket
+	routes  []*routeLimit
+
+	now  func() time.Time
+	lock sync.Mutex
+}
+
+type routeLimit struct {
+	method  string
+	pattern *regexp.Regexp
+
+	preview *bucket
+	prod    *bucket
+}
+
+// bucket is a token bucket. tokens is the number of tokens at last.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket allowing ratePreview and rateProd requests per second in
+// the preview and production environments, with bursts of up to burst requests. Rates default to
+// the same values as NewRedis, and burst defaults to the rate (at least 1).
+func NewTokenBucket(ratePreview, rateProd float64, burst int) *TokenBucket {
+	if ratePreview <= 0 {
+		ratePreview = defaultRatePerSecPreview
+	}
+
+	if rateProd <= 0 {
+		rateProd = defaultRatePerSecProd
+	}
+
+	t := &TokenBucket{
+		now: time.Now,
+	}
+
+	t.preview = newBucket(ratePreview, burst)
+	t.prod = newBucket(rateProd, burst)
+
+	return t
+}
+
+// WithRouteLimit adds a limit for requests whose method and path match, in addition to the
+// environment-wide limit. An empty method matches every method. pattern is a regular expression
+// matched against the request path relative to the practice, e.g. `^/appointments/open$` or
+// `^/patients/\d+/documents`. All requests matching the pattern share the same bucket.
+func (t *TokenBucket) WithRouteLimit(method, pattern string, ratePreview, rateProd float64, burst int) *TokenBucket {
+	if ratePreview <= 0 || rateProd <= 0 {
+		panic("rates must be positive")
+	}
+
+	t.routes = append(t.routes, &routeLimit{
+		method:  method,
+		pattern: regexp.MustCompile(pattern),
+
+		preview: newBucket(ratePreview, burst),
+		prod:    newBucket(rateProd, burst),
+	})
+
+	return t
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	b := float64(burst)
+	if burst <= 0 {
+		b = math.Max(math.Floor(rate), 1)
+	}
+
+	return &bucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+	}
+}
+
+// Allowed takes a token from the environment's bucket and from the bucket of every route limit
+// matching the route in ctx (see ContextWithRoute). If any of them is empty, no tokens are taken
+// and ErrRateExceeded is returned along with the time until all of them have a token.
+func (t *TokenBucket) Allowed(ctx context.Context, preview bool) (time.Duration, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+
+	buckets := []*bucket{t.prod}
+	if preview {
+		buckets[0] = t.preview
+	}
+
+	if route, ok := RouteFromContext(ctx); ok {
+		for _, r := range t.routes {
+			if len(r.method) > 0 && !strings.EqualFold(r.method, route.Method) {
+				continue
+			}
+
+			if !r.pattern.MatchString(route.Path) {
+				continue
+			}
+
+			if preview {
+				buckets = append(buckets, r.preview)
+			} else {
+				buckets = append(buckets, r.prod)
+			}
+		}
+	}
+
+	var retryAfter time.Duration
+
+	for _, b := range buckets {
+		b.refill(now)
+		retryAfter = max(retryAfter, b.wait())
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, ErrRateExceeded
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+
+	return 0, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+
+	b.last = now
+}
+
+// wait returns the time until the bucket has a token.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+}
@@ -0,0 +1,150 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTokenBucket(ratePreview, rateProd float64, burst int) (*TokenBucket, *time.Time) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t := NewTokenBucket(ratePreview, rateProd, burst)
+	t.now = func() time.Time {
+		return now
+	}
+
+	return t, &now
+}
+
+func TestTokenBucket_Allowed(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, now := newTestTokenBucket(2, 100, 2)
+
+	for range 2 {
+		retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+		assert.Zero(retryAfter)
+		assert.NoError(err)
+	}
+
+	retryAfter, err := rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Equal(500*time.Millisecond, retryAfter)
+
+	// Production has its own bucket.
+	retryAfter, err = rateLimiter.Allowed(context.Background(), false)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	*now = now.Add(200 * time.Millisecond)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Equal(300*time.Millisecond, retryAfter)
+
+	*now = now.Add(retryAfter)
+
+	retryAfter, err = rateLimiter.Allowed(context.Background(), true)
+	assert.Zero(retryAfter)
+	assert.NoError(err)
+
+	// Tokens never exceed the burst.
+	*now = now.Add(time.Hour)
+
+	for range 2 {
+		_, err = rateLimiter.Allowed(context.Background(), true)
+		assert.NoError(err)
+	}
+
+	_, err = rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+}
+
+func TestTokenBucket_Allowed_routeLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, now := newTestTokenBucket(10, 10, 10)
+	rateLimiter.WithRouteLimit("GET", `^/appointments/open$`, 1, 1, 1)
+
+	openCtx := ContextWithRoute(context.Background(), "GET", "/appointments/open?departmentid=1")
+
+	_, err := rateLimiter.Allowed(openCtx, true)
+	assert.NoError(err)
+
+	retryAfter, err := rateLimiter.Allowed(openCtx, true)
+	assert.ErrorIs(err, ErrRateExceeded)
+	assert.Equal(time.Second, retryAfter)
+
+	// Other routes, methods and requests without a route only use the environment bucket.
+	_, err = rateLimiter.Allowed(ContextWithRoute(context.Background(), "GET", "/patients"), true)
+	assert.NoError(err)
+
+	_, err = rateLimiter.Allowed(ContextWithRoute(context.Background(), "POST", "/appointments/open"), true)
+	assert.NoError(err)
+
+	_, err = rateLimiter.Allowed(context.Background(), true)
+	assert.NoError(err)
+
+	// A rejected request does not take a token from the environment bucket: 10 - 4 remain.
+	for range 6 {
+		_, err = rateLimiter.Allowed(context.Background(), true)
+		assert.NoError(err)
+	}
+
+	_, err = rateLimiter.Allowed(context.Background(), true)
+	assert.ErrorIs(err, ErrRateExceeded)
+
+	*now = now.Add(time.Second)
+
+	_, err = rateLimiter.Allowed(openCtx, true)
+	assert.NoError(err)
+}
+
+func TestTokenBucket_Allowed_concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter, _ := newTestTokenBucket(5, 5, 5)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	allowed := 0
+
+	for range 20 {
+		wg.Go(func() {
+			_, err := rateLimiter.Allowed(context.Background(), true)
+			if err == nil {
+				lock.Lock()
+				allowed++
+				lock.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(5, allowed)
+}
+
+func TestNewTokenBucket_defaults(t *testing.T) {
+	assert := assert.New(t)
+
+	rateLimiter := NewTokenBucket(0, 0, 0)
+	assert.Equal(float64(defaultRatePerSecPreview), rateLimiter.preview.rate)
+	assert.Equal(float64(defaultRatePerSecPreview), rateLimiter.preview.burst)
+	assert.Equal(float64(defaultRatePerSecProd), rateLimiter.prod.rate)
+
+	rateLimiter = NewTokenBucket(0.5, 1, 0)
+	assert.Equal(float64(1), rateLimiter.preview.burst)
+}
+
+func TestTokenBucket_WithRouteLimit_invalidRate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewTokenBucket(1, 1, 1).WithRouteLimit("", "/documents", 0, 1, 1)
+	})
+}
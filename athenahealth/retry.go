@@ -0,0 +1,185 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 250 * time.Millisecond
+	defaultRetryMaxDelay    = 5 * time.Second
+)
+
+// RetryPolicy controls how HTTPClient retries requests that fail with a 429, 502, 503 or 504
+// response or with a transport error. Delays grow exponentially from BaseDelay up to MaxDelay
+// with full jitter, unless athenahealth sends a Retry-After header, which is honored instead.
+//
+// Requests with non-idempotent methods (POST) are only retried after a 429, which athenahealth
+// returns before processing the request, unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	// RetryNonIdempotent allows POST requests (e.g. CreatePatient, CreateFinancialClaim) to be
+	// retried after transport errors and 5xx responses, which may result in duplicate records.
+	RetryNonIdempotent bool
+}
+
+// NewRetryPolicy returns a RetryPolicy with sensible defaults.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+	}
+}
+
+// transportError wraps an error returned by the underlying *http.Client so it can be told apart
+// from errors returned by the rate limiter, token provider or token cacher.
+type transportError struct {
+	err error
+}
+
+func (t *transportError) Error() string {
+	return t.err.Error()
+}
+
+func (t *transportError) Unwrap() error {
+	return t.err
+}
+
+// retryDelay reports whether the request should be retried after the given attempt and, if so,
+// how long to wait before the next one.
+func (p *RetryPolicy) retryDelay(ctx context.Context, method string, attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	idempotent := p.RetryNonIdempotent || isIdempotentMethod(method)
+
+	var tErr *transportError
+	if errors.As(err, &tErr) {
+		if !idempotent {
+			return 0, false
+		}
+
+		return p.backoff(attempt), true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPResponse == nil {
+		return 0, false
+	}
+
+	switch apiErr.HTTPResponse.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !idempotent {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if retryAfter, ok := parseRetryAfter(apiErr.HTTPResponse.Header.Get("Retry-After")); ok {
+		return retryAfter, true
+	}
+
+	return p.backoff(attempt), true
+}
+
+// backoff returns a random delay between 0 and BaseDelay * 2^(attempt-1), capped at MaxDelay.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.MaxDelay
+	if p.BaseDelay > 0 && attempt-1 < 32 {
+		exp := p.BaseDelay << (attempt - 1)
+		if exp > 0 && (ceiling <= 0 || exp < ceiling) {
+			ceiling = exp
+		}
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header value in either delay-seconds or HTTP-date form.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
+
+// replayableBody returns a function that yields a fresh reader over body for every attempt and
+// whether body can be replayed at all. Streaming bodies, such as the io.Pipe used by
+// PostFormReader, can only be read once.
+func replayableBody(body io.Reader) (func() io.Reader, bool) {
+	switch b := body.(type) {
+	case nil:
+		return func() io.Reader { return nil }, true
+
+	case *bytes.Buffer:
+		buf := b.Bytes()
+
+		return func() io.Reader { return bytes.NewReader(buf) }, true
+
+	case *bytes.Reader:
+		snapshot := *b
+
+		return func() io.Reader {
+			r := snapshot
+			return &r
+		}, true
+
+	case *strings.Reader:
+		snapshot := *b
+
+		return func() io.Reader {
+			r := snapshot
+			return &r
+		}, true
+	}
+
+	return func() io.Reader { return body }, false
+}
@@ -0,0 +1,246 @@
+package athenahealth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+}
+
+func TestHTTPClient_request_retry(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"msg":"Hello World!"}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	var out map[string]string
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, &out)
+
+	assert.NotNil(res)
+	assert.NoError(err)
+	assert.Equal("Hello World!", out["msg"])
+	assert.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_request_retry_exhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	res, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.NotNil(res)
+	assert.IsType(&APIError{}, err)
+	assert.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_request_retry_disabled(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.Error(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_request_retry_POST(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	var bodies []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	values := url.Values{}
+	values.Add("foo", "bar")
+
+	// 429s are retried for POSTs, 503s are not unless RetryNonIdempotent is set.
+	_, err := athenaClient.PostForm(context.Background(), "/", values, nil)
+
+	assert.IsType(&APIError{}, err)
+	assert.Equal(int32(2), atomic.LoadInt32(&calls))
+	assert.Equal([]string{"foo=bar", "foo=bar"}, bodies)
+
+	atomic.StoreInt32(&calls, 0)
+	bodies = nil
+
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+	athenaClient.WithRetryPolicy(policy)
+
+	_, err = athenaClient.PostForm(context.Background(), "/", values, nil)
+
+	assert.NoError(err)
+	assert.Equal(int32(3), atomic.LoadInt32(&calls))
+	assert.Equal([]string{"foo=bar", "foo=bar", "foo=bar"}, bodies)
+}
+
+func TestHTTPClient_request_retry_streaming_body(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	fue := NewFormURLEncoder()
+	fue.AddString("foo", "bar")
+
+	_, err := athenaClient.PostFormReader(context.Background(), "/", fue, nil)
+
+	assert.IsType(&APIError{}, err)
+	assert.Equal(int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_request_retry_transport_error(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	ts.Close()
+
+	var calls int32
+	athenaClient.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, io.ErrUnexpectedEOF
+		}),
+	}
+	athenaClient.WithRetryPolicy(testRetryPolicy())
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	assert.ErrorIs(err, io.ErrUnexpectedEOF)
+	assert.IsType(&url.Error{}, err)
+	assert.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &RetryPolicy{
+		BaseDelay: 10 * time.Millisecond,
+		MaxDelay:  50 * time.Millisecond,
+	}
+
+	for range 100 {
+		assert.LessOrEqual(p.backoff(1), 10*time.Millisecond)
+		assert.LessOrEqual(p.backoff(3), 40*time.Millisecond)
+		assert.LessOrEqual(p.backoff(10), 50*time.Millisecond)
+		assert.LessOrEqual(p.backoff(100), 50*time.Millisecond)
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	d, ok := parseRetryAfter("3")
+	assert.True(ok)
+	assert.Equal(3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(ok)
+	assert.Greater(d, 59*time.Minute)
+
+	_, ok = parseRetryAfter("")
+	assert.False(ok)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(ok)
+}
+
+func Test_replayableBody(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, body := range []io.Reader{
+		strings.NewReader("foo"),
+		bytes.NewReader([]byte("foo")),
+		bytes.NewBufferString("foo"),
+	} {
+		next, ok := replayableBody(body)
+		assert.True(ok)
+
+		for range 2 {
+			b, _ := io.ReadAll(next())
+			assert.Equal("foo", string(b))
+		}
+	}
+
+	next, ok := replayableBody(nil)
+	assert.True(ok)
+	assert.Nil(next())
+
+	pr, _ := io.Pipe()
+	_, ok = replayableBody(pr)
+	assert.False(ok)
+}
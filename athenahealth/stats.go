@@ -0,0 +1,74 @@
+package athenahealth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+)
+
+type requestStatKey struct{}
+
+// contextWithRequestStat returns a copy of ctx carrying stat, so send can record the bytes it sent
+// and received for the attempt.
+func contextWithRequestStat(ctx context.Context, stat *stats.RequestStat) context.Context {
+	return context.WithValue(ctx, requestStatKey{}, stat)
+}
+
+func requestStatFromContext(ctx context.Context) *stats.RequestStat {
+	stat, _ := ctx.Value(requestStatKey{}).(*stats.RequestStat)
+
+	return stat
+}
+
+func (h *HTTPClient) newRequestStat(c *call) *stats.RequestStat {
+	return &stats.RequestStat{
+		PracticeID: h.practiceID,
+		Preview:    h.preview,
+		Method:     c.method,
+		Route:      stats.CleanPath(c.path),
+		Attempt:    c.attempt,
+	}
+}
+
+func (h *HTTPClient) requestStarted(stat *stats.RequestStat) {
+	if s, ok := h.stats.(StatsV2); ok {
+		h.logStatsError(s.RequestStarted(stat))
+	}
+}
+
+func (h *HTTPClient) requestCompleted(stat *stats.RequestStat) {
+	if s, ok := h.stats.(StatsV2); ok {
+		h.logStatsError(s.RequestCompleted(stat))
+	}
+}
+
+// responseReceived records res with Stats that do not implement StatsV2.
+func (h *HTTPClient) responseReceived(req *Request, res *http.Response) {
+	if _, ok := h.stats.(StatsV2); ok {
+		return
+	}
+
+	h.logStatsError(h.stats.Request(req.Method, req.Path))
+
+	if isErrorResponse(res) {
+		h.logStatsError(h.stats.ResponseError())
+	} else {
+		h.logStatsError(h.stats.ResponseSuccess())
+	}
+}
+
+func (h *HTTPClient) tokenRefreshed(stat *stats.TokenRefreshStat) {
+	if s, ok := h.stats.(StatsV2); ok {
+		h.logStatsError(s.TokenRefreshed(stat))
+	}
+}
+
+// logStatsError logs err, if any. Stats failures never fail a request.
+func (h *HTTPClient) logStatsError(err error) {
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Msg("athenahealth stats failed")
+	}
+}
@@ -1,14 +1,18 @@
 package stats
 
 import (
+	"errors"
 	"net/url"
 	"regexp"
+	"strconv"
 
 	"github.com/DataDog/datadog-go/statsd"
 )
 
 var idRegex = regexp.MustCompile(`(/)(\d+)(/?)`)
 
+// Datadog sends stats to DogStatsD. As a StatsV2, it tags request metrics with the method, cleaned
+// route, status code, status class and practice.
 type Datadog struct {
 	client statsd.ClientInterface
 }
@@ -20,7 +24,7 @@ func NewDatadog(client statsd.ClientInterface) *Datadog {
 }
 
 func (d *Datadog) Request(method, path string) error {
-	path = cleanPath(path)
+	path = CleanPath(path)
 
 	return d.client.Incr("athenahealth.requests", []string{
 		"http_method:" + method,
@@ -36,7 +40,9 @@ func (d *Datadog) ResponseError() error {
 	return d.client.Incr("athenahealth.responses.error", []string{}, 1.0)
 }
 
-func cleanPath(path string) string {
+// CleanPath strips the query string from path and replaces numeric IDs with :id: so it can be
+// used as a low-cardinality route (e.g. /patients/123/documents becomes /patients/:id:/documents).
+func CleanPath(path string) string {
 	u, err := url.Parse(path)
 	if err != nil {
 		return ""
@@ -44,3 +50,60 @@ func cleanPath(path string) string {
 
 	return idRegex.ReplaceAllString(u.Path, "$1:id:$3")
 }
+
+// RequestStarted does nothing; Datadog records requests when they complete.
+func (d *Datadog) RequestStarted(stat *RequestStat) error {
+	return nil
+}
+
+func (d *Datadog) RequestCompleted(stat *RequestStat) error {
+	tags := []string{
+		"http_method:" + stat.Method,
+		"http_path:" + stat.Route,
+		"http_status_code:" + strconv.Itoa(stat.StatusCode),
+		"http_status_class:" + StatusClass(stat.StatusCode),
+		"practice_id:" + stat.PracticeID,
+		"environment:" + environment(stat.Preview),
+	}
+
+	response := "athenahealth.responses.success"
+	if stat.StatusCode < 200 || stat.StatusCode >= 300 {
+		response = "athenahealth.responses.error"
+	}
+
+	return errors.Join(
+		d.client.Incr("athenahealth.requests", tags, 1.0),
+		d.client.Incr(response, tags, 1.0),
+		d.client.Timing("athenahealth.request.duration", stat.Duration, tags, 1.0),
+		d.client.Histogram("athenahealth.request.bytes_sent", float64(stat.BytesSent), tags, 1.0),
+		d.client.Histogram("athenahealth.request.bytes_received", float64(stat.BytesReceived), tags, 1.0),
+		d.client.Timing("athenahealth.rate_limit.wait", stat.RateLimitWait, tags, 1.0),
+	)
+}
+
+func (d *Datadog) TokenRefreshed(stat *TokenRefreshStat) error {
+	result := "success"
+	if stat.Err != nil {
+		result = "error"
+	}
+
+	tags := []string{
+		"result:" + result,
+		"background:" + strconv.FormatBool(stat.Background),
+		"practice_id:" + stat.PracticeID,
+		"environment:" + environment(stat.Preview),
+	}
+
+	return errors.Join(
+		d.client.Incr("athenahealth.token_refresh", tags, 1.0),
+		d.client.Timing("athenahealth.token_refresh.duration", stat.Duration, tags, 1.0),
+	)
+}
+
+func environment(preview bool) string {
+	if preview {
+		return "preview"
+	}
+
+	return "prod"
+}
@@ -1,7 +1,9 @@
 package stats
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
 	"github.com/stretchr/testify/assert"
@@ -9,13 +11,23 @@ import (
 
 type mockClient struct {
 	statsd.ClientInterface
-	incrFn func(name string, tags []string, rate float64) error
+	incrFn      func(name string, tags []string, rate float64) error
+	timingFn    func(name string, value time.Duration, tags []string, rate float64) error
+	histogramFn func(name string, value float64, tags []string, rate float64) error
 }
 
 func (m *mockClient) Incr(name string, tags []string, rate float64) error {
 	return m.incrFn(name, tags, rate)
 }
 
+func (m *mockClient) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return m.timingFn(name, value, tags, rate)
+}
+
+func (m *mockClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	return m.histogramFn(name, value, tags, rate)
+}
+
 func TestDatadog_Request(t *testing.T) {
 	assert := assert.New(t)
 
@@ -36,8 +48,95 @@ func TestDatadog_Request(t *testing.T) {
 func TestRemoveIDsFromPath(t *testing.T) {
 	assert := assert.New(t)
 
-	assert.Equal("/patients/:id:", cleanPath("/patients/123"))
-	assert.Equal("/patients/:id:", cleanPath("/patients/123?foo=bar"))
-	assert.Equal("/patients/:id:/foo/:id:", cleanPath("/patients/123/foo/1"))
-	assert.Equal("/patients/:id:/foo/:id:/", cleanPath("/patients/123/foo/1/"))
+	assert.Equal("/patients/:id:", CleanPath("/patients/123"))
+	assert.Equal("/patients/:id:", CleanPath("/patients/123?foo=bar"))
+	assert.Equal("/patients/:id:/foo/:id:", CleanPath("/patients/123/foo/1"))
+	assert.Equal("/patients/:id:/foo/:id:/", CleanPath("/patients/123/foo/1/"))
+}
+
+func TestDatadog_RequestCompleted(t *testing.T) {
+	assert := assert.New(t)
+
+	expectedTags := []string{
+		"http_method:GET",
+		"http_path:/patients/:id:",
+		"http_status_code:404",
+		"http_status_class:4xx",
+		"practice_id:195900",
+		"environment:preview",
+	}
+
+	incrs := []string{}
+	timings := map[string]time.Duration{}
+	histograms := map[string]float64{}
+
+	client := &mockClient{
+		incrFn: func(name string, tags []string, rate float64) error {
+			assert.Equal(expectedTags, tags)
+			incrs = append(incrs, name)
+			return nil
+		},
+		timingFn: func(name string, value time.Duration, tags []string, rate float64) error {
+			assert.Equal(expectedTags, tags)
+			timings[name] = value
+			return nil
+		},
+		histogramFn: func(name string, value float64, tags []string, rate float64) error {
+			assert.Equal(expectedTags, tags)
+			histograms[name] = value
+			return errors.New("statsd is down")
+		},
+	}
+
+	datadog := NewDatadog(client)
+
+	err := datadog.RequestCompleted(&RequestStat{
+		PracticeID:    "195900",
+		Preview:       true,
+		Method:        "GET",
+		Route:         "/patients/:id:",
+		StatusCode:    404,
+		Duration:      time.Millisecond * 200,
+		BytesSent:     10,
+		BytesReceived: 20,
+		RateLimitWait: time.Millisecond * 50,
+	})
+	assert.Error(err)
+
+	assert.Equal([]string{"athenahealth.requests", "athenahealth.responses.error"}, incrs)
+	assert.Equal(map[string]time.Duration{
+		"athenahealth.request.duration": time.Millisecond * 200,
+		"athenahealth.rate_limit.wait":  time.Millisecond * 50,
+	}, timings)
+	assert.Equal(map[string]float64{
+		"athenahealth.request.bytes_sent":     10,
+		"athenahealth.request.bytes_received": 20,
+	}, histograms)
+}
+
+func TestDatadog_TokenRefreshed(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &mockClient{
+		incrFn: func(name string, tags []string, rate float64) error {
+			assert.Equal("athenahealth.token_refresh", name)
+			assert.Equal([]string{"result:error", "background:true", "practice_id:195900", "environment:prod"}, tags)
+			return nil
+		},
+		timingFn: func(name string, value time.Duration, tags []string, rate float64) error {
+			assert.Equal("athenahealth.token_refresh.duration", name)
+			assert.Equal(time.Second, value)
+			return nil
+		},
+	}
+
+	datadog := NewDatadog(client)
+
+	err := datadog.TokenRefreshed(&TokenRefreshStat{
+		PracticeID: "195900",
+		Background: true,
+		Duration:   time.Second,
+		Err:        errors.New("invalid_client"),
+	})
+	assert.NoError(err)
 }
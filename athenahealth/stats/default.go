@@ -18,3 +18,15 @@ func (d *Default) ResponseSuccess() error {
 func (d *Default) ResponseError() error {
 	return nil
 }
+
+func (d *Default) RequestStarted(stat *RequestStat) error {
+	return nil
+}
+
+func (d *Default) RequestCompleted(stat *RequestStat) error {
+	return nil
+}
+
+func (d *Default) TokenRefreshed(stat *TokenRefreshStat) error {
+	return nil
+}
@@ -29,3 +29,12 @@ func TestDefault_ResponseError(t *testing.T) {
 	err := stats.ResponseError()
 	assert.NoError(err)
 }
+
+func TestDefault_StatsV2(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := NewDefault()
+	assert.NoError(stats.RequestStarted(&RequestStat{}))
+	assert.NoError(stats.RequestCompleted(&RequestStat{}))
+	assert.NoError(stats.TokenRefreshed(&TokenRefreshStat{}))
+}
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusDurationBuckets are the buckets, in seconds, of Prometheus' request duration histogram.
+var PrometheusDurationBuckets = []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Prometheus records stats as Prometheus metrics:
+//
+//   - athenahealth_requests_total, a counter of request attempts
+//   - athenahealth_request_duration_seconds, a histogram of request attempt latency
+//   - athenahealth_requests_in_flight, a gauge of request attempts awaiting a response
+//   - athenahealth_token_refreshes_total, a counter of calls to the token provider
+//
+// Request metrics are labeled by practice, environment, method, route (IDs collapsed by CleanPath)
+// and, once a response is received, status class. Token refreshes are labeled by practice,
+// environment, whether they were made in the background and result. Since the practice is a
+// label, a single Prometheus can be shared by the HTTPClients of several practices.
+type Prometheus struct {
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	inFlight       *prometheus.GaugeVec
+	tokenRefreshes *prometheus.CounterVec
+}
+
+// NewPrometheus registers Prometheus' metrics with registerer. If they are already registered
+// (e.g. by another call to NewPrometheus with the same registerer), the registered metrics are
+// used.
+func NewPrometheus(registerer prometheus.Registerer) (*Prometheus, error) {
+	if registerer == nil {
+		panic("registerer is nil")
+	}
+
+	requestLabels := []string{"practice_id", "environment", "method", "route"}
+
+	p := &Prometheus{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_requests_total",
+			Help: "Number of athenahealth API request attempts.",
+		}, append(requestLabels, "status_class")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "athenahealth_request_duration_seconds",
+			Help:    "Latency of athenahealth API request attempts.",
+			Buckets: PrometheusDurationBuckets,
+		}, append(requestLabels, "status_class")),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "athenahealth_requests_in_flight",
+			Help: "Number of athenahealth API request attempts awaiting a response.",
+		}, requestLabels),
+		tokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "athenahealth_token_refreshes_total",
+			Help: "Number of calls to the athenahealth token provider.",
+		}, []string{"practice_id", "environment", "background", "result"}),
+	}
+
+	var err error
+
+	p.requests, err = register(registerer, p.requests)
+	if err != nil {
+		return nil, err
+	}
+
+	p.duration, err = register(registerer, p.duration)
+	if err != nil {
+		return nil, err
+	}
+
+	p.inFlight, err = register(registerer, p.inFlight)
+	if err != nil {
+		return nil, err
+	}
+
+	p.tokenRefreshes, err = register(registerer, p.tokenRefreshes)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// register registers c with registerer and returns it, or the collector already registered in its
+// place.
+func register[C prometheus.Collector](registerer prometheus.Registerer, c C) (C, error) {
+	err := registerer.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+
+	return c, err
+}
+
+// Request does nothing; Prometheus records requests through RequestStarted and RequestCompleted.
+func (p *Prometheus) Request(method, path string) error {
+	return nil
+}
+
+// ResponseSuccess does nothing; Prometheus records responses through RequestCompleted.
+func (p *Prometheus) ResponseSuccess() error {
+	return nil
+}
+
+// ResponseError does nothing; Prometheus records responses through RequestCompleted.
+func (p *Prometheus) ResponseError() error {
+	return nil
+}
+
+func (p *Prometheus) RequestStarted(stat *RequestStat) error {
+	p.inFlight.WithLabelValues(stat.PracticeID, environment(stat.Preview), stat.Method, stat.Route).Inc()
+
+	return nil
+}
+
+func (p *Prometheus) RequestCompleted(stat *RequestStat) error {
+	env := environment(stat.Preview)
+	statusClass := StatusClass(stat.StatusCode)
+
+	p.inFlight.WithLabelValues(stat.PracticeID, env, stat.Method, stat.Route).Dec()
+	p.requests.WithLabelValues(stat.PracticeID, env, stat.Method, stat.Route, statusClass).Inc()
+	p.duration.WithLabelValues(stat.PracticeID, env, stat.Method, stat.Route, statusClass).Observe(stat.Duration.Seconds())
+
+	return nil
+}
+
+func (p *Prometheus) TokenRefreshed(stat *TokenRefreshStat) error {
+	result := "success"
+	if stat.Err != nil {
+		result = "error"
+	}
+
+	p.tokenRefreshes.WithLabelValues(stat.PracticeID, environment(stat.Preview), strconv.FormatBool(stat.Background), result).Inc()
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheus(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+
+	p, err := NewPrometheus(registry)
+	assert.NoError(err)
+
+	stat := &RequestStat{
+		PracticeID: "195900",
+		Preview:    true,
+		Method:     "GET",
+		Route:      CleanPath("/patients/123"),
+	}
+
+	assert.NoError(p.RequestStarted(stat))
+	assert.Equal(float64(1), testutil.ToFloat64(p.inFlight.WithLabelValues("195900", "preview", "GET", "/patients/:id:")))
+
+	stat.StatusCode = 404
+	stat.Duration = time.Millisecond * 200
+
+	assert.NoError(p.RequestCompleted(stat))
+	assert.Equal(float64(0), testutil.ToFloat64(p.inFlight.WithLabelValues("195900", "preview", "GET", "/patients/:id:")))
+	assert.Equal(float64(1), testutil.ToFloat64(p.requests.WithLabelValues("195900", "preview", "GET", "/patients/:id:", "4xx")))
+	assert.Equal(1, testutil.CollectAndCount(p.duration))
+
+	assert.NoError(p.TokenRefreshed(&TokenRefreshStat{PracticeID: "195900", Background: true, Err: errors.New("invalid_client")}))
+	assert.Equal(float64(1), testutil.ToFloat64(p.tokenRefreshes.WithLabelValues("195900", "prod", "true", "error")))
+}
+
+func TestPrometheus_sharedRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+
+	p1, err := NewPrometheus(registry)
+	assert.NoError(err)
+
+	p2, err := NewPrometheus(registry)
+	assert.NoError(err)
+
+	for _, stat := range []*RequestStat{
+		{PracticeID: "1", Method: "GET", Route: "/patients/:id:", StatusCode: 200},
+		{PracticeID: "2", Method: "GET", Route: "/patients/:id:", StatusCode: 200},
+	} {
+		assert.NoError(p1.RequestCompleted(stat))
+		assert.NoError(p2.RequestCompleted(stat))
+	}
+
+	assert.Equal(float64(2), testutil.ToFloat64(p1.requests.WithLabelValues("1", "prod", "GET", "/patients/:id:", "2xx")))
+	assert.Equal(float64(2), testutil.ToFloat64(p2.requests.WithLabelValues("2", "prod", "GET", "/patients/:id:", "2xx")))
+
+	count, err := testutil.GatherAndCount(registry, "athenahealth_requests_total")
+	assert.NoError(err)
+	assert.Equal(2, count)
+}
+
+func TestPrometheus_registerError(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "athenahealth_requests_total",
+		Help: "Conflicting.",
+	}))
+
+	p, err := NewPrometheus(registry)
+
+	assert.Nil(p)
+	assert.Error(err)
+}
+
+func TestNewPrometheus_nil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		_, _ = NewPrometheus(nil)
+	})
+}
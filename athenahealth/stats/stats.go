@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"strconv"
+	"time"
+)
+
+// RequestStat describes a single attempt of an athenahealth API request.
+type RequestStat struct {
+	PracticeID string
+	Preview    bool
+	Method     string
+	// Route is the request path with IDs collapsed by CleanPath.
+	Route   string
+	Attempt int
+
+	// StatusCode is 0 if no response was received (e.g. the connection failed).
+	StatusCode    int
+	Duration      time.Duration
+	BytesSent     int64
+	BytesReceived int64
+	// RateLimitWait is how long the attempt waited for the rate limiter.
+	RateLimitWait time.Duration
+}
+
+// TokenRefreshStat describes a call to the token provider.
+type TokenRefreshStat struct {
+	PracticeID string
+	Preview    bool
+	// Background is set for refreshes made by HTTPClient.StartTokenRefresh rather than by a
+	// request that found the cached token expired.
+	Background bool
+	Duration   time.Duration
+	Err        error
+}
+
+// StatusClass returns the class of statusCode, e.g. "2xx", or "none" if statusCode is 0.
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 999 {
+		return "none"
+	}
+
+	return strconv.Itoa(statusCode/100) + "xx"
+}
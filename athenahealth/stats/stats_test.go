@@ -0,0 +1,16 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusClass(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2xx", StatusClass(200))
+	assert.Equal("4xx", StatusClass(429))
+	assert.Equal("5xx", StatusClass(503))
+	assert.Equal("none", StatusClass(0))
+}
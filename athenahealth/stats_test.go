@@ -0,0 +1,169 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ StatsV2 = (*stats.Default)(nil)
+	_ StatsV2 = (*stats.Datadog)(nil)
+	_ StatsV2 = (*stats.Prometheus)(nil)
+	_ Stats   = (*stats.Prometheus)(nil)
+)
+
+type testStatsV2 struct {
+	testStats
+
+	lock           sync.Mutex
+	started        []stats.RequestStat
+	completed      []stats.RequestStat
+	tokenRefreshes []stats.TokenRefreshStat
+	err            error
+}
+
+func (t *testStatsV2) RequestStarted(stat *stats.RequestStat) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.started = append(t.started, *stat)
+
+	return t.err
+}
+
+func (t *testStatsV2) RequestCompleted(stat *stats.RequestStat) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.completed = append(t.completed, *stat)
+
+	return t.err
+}
+
+func (t *testStatsV2) TokenRefreshed(stat *stats.TokenRefreshStat) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.tokenRefreshes = append(t.tokenRefreshes, *stat)
+
+	return t.err
+}
+
+func TestHTTPClient_stats_error(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	called := false
+	athenaClient.WithStats(&testStats{
+		RequestFunc: func(method, path string) error {
+			called = true
+			assert.Equal("GET", method)
+			assert.Equal("/patients/1", path)
+
+			return errors.New("statsd is down")
+		},
+		ResponseSuccessFunc: func() error {
+			return errors.New("statsd is down")
+		},
+	})
+
+	res, err := athenaClient.request(context.Background(), "GET", "/patients/1", nil, nil, nil)
+
+	assert.NoError(err)
+	assert.NotNil(res)
+	assert.True(called)
+}
+
+func TestHTTPClient_statsV2(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"The patient is not available."}`))
+	})
+	defer ts.Close()
+
+	rateLimited := false
+	athenaClient.WithRateLimiter(&testRateLimiter{
+		AllowedFunc: func(preview bool) (time.Duration, error) {
+			if !rateLimited {
+				rateLimited = true
+
+				return time.Millisecond * 10, ratelimiter.ErrRateExceeded
+			}
+
+			return 0, nil
+		},
+	})
+
+	s := &testStatsV2{
+		testStats: testStats{
+			RequestFunc: func(method, path string) error {
+				assert.Fail("Stats.Request called for StatsV2")
+
+				return nil
+			},
+		},
+		err: errors.New("statsd is down"),
+	}
+	athenaClient.WithStats(s)
+
+	_, err := athenaClient.request(context.Background(), "POST", "/patients/123?foo=bar", strings.NewReader("a=b"), nil, nil)
+	assert.ErrorIs(err, ErrNotFound)
+
+	assert.Len(s.started, 1)
+	assert.Equal("/patients/:id:", s.started[0].Route)
+
+	if assert.Len(s.completed, 1) {
+		stat := s.completed[0]
+
+		assert.Equal(testPracticeID, stat.PracticeID)
+		assert.True(stat.Preview)
+		assert.Equal("POST", stat.Method)
+		assert.Equal("/patients/:id:", stat.Route)
+		assert.Equal(1, stat.Attempt)
+		assert.Equal(http.StatusNotFound, stat.StatusCode)
+		assert.Equal(int64(3), stat.BytesSent)
+		assert.Equal(int64(len(`{"error":"The patient is not available."}`)), stat.BytesReceived)
+		assert.GreaterOrEqual(stat.RateLimitWait, time.Millisecond*10)
+		assert.Positive(stat.Duration)
+	}
+}
+
+func TestHTTPClient_statsV2_tokenRefreshed(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	s := &testStatsV2{}
+	athenaClient.
+		WithTokenProvider(&testTokenProvider{token: testToken}).
+		WithTokenCacher(tokencacher.NewDefault()).
+		WithStats(s)
+
+	for range 2 {
+		_, err := athenaClient.request(context.Background(), "GET", "/", nil, nil, nil)
+		assert.NoError(err)
+	}
+
+	if assert.Len(s.tokenRefreshes, 1) {
+		assert.Equal(testPracticeID, s.tokenRefreshes[0].PracticeID)
+		assert.False(s.tokenRefreshes[0].Background)
+		assert.NoError(s.tokenRefreshes[0].Err)
+	}
+
+	assert.Len(s.completed, 2)
+}
@@ -4,6 +4,20 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
+	"sort"
+)
+
+// FeedType is an athenahealth changed data feed that can be subscribed to.
+type FeedType string
+
+const (
+	FeedTypeAppointments  FeedType = "appointments"
+	FeedTypeLabResults    FeedType = "labresults"
+	FeedTypePatients      FeedType = "patients"
+	FeedTypePrescriptions FeedType = "prescriptions"
+	FeedTypeProblems      FeedType = "chart/healthhistory/problems"
+	FeedTypeProviders     FeedType = "providers"
 )
 
 type Subscription struct {
@@ -16,7 +30,7 @@ type Subscription struct {
 // GET /v1/{practiceid}/appointments/changed/subscription
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-appointment-slot-change-subscription(s)
-func (h *HTTPClient) GetSubscription(ctx context.Context, feedType string) (*Subscription, error) {
+func (h *HTTPClient) GetSubscription(ctx context.Context, feedType FeedType) (*Subscription, error) {
 	out := &Subscription{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/%s/changed/subscription", feedType), nil, out)
@@ -40,7 +54,7 @@ type listSubscriptionEventsResponse struct {
 // GET /v1/{practiceid}/appointments/changed/subscription/events
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Get-list-of-appointment-slot-change-events-to-which-you-can-subscribe
-func (h *HTTPClient) ListSubscriptionEvents(ctx context.Context, feedType string) ([]*SubscriptionEvent, error) {
+func (h *HTTPClient) ListSubscriptionEvents(ctx context.Context, feedType FeedType) ([]*SubscriptionEvent, error) {
 	out := &listSubscriptionEventsResponse{}
 
 	_, err := h.Get(ctx, fmt.Sprintf("/%s/changed/subscription/events", feedType), nil, &out)
@@ -60,7 +74,7 @@ type SubscribeOptions struct {
 // POST /v1/{practiceid}/appointments/changed/subscription
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Subscribe-to-all/specific-change-events-for-appointment-slots
-func (h *HTTPClient) Subscribe(ctx context.Context, feedType string, opts *SubscribeOptions) error {
+func (h *HTTPClient) Subscribe(ctx context.Context, feedType FeedType, opts *SubscribeOptions) error {
 	var form url.Values
 
 	if opts != nil {
@@ -85,7 +99,7 @@ type UnsubscribeOptions struct {
 // POST /v1/{practiceid}/appointments/changed/subscription
 //
 // https://docs.athenahealth.com/api/api-ref/appointment#Unsubscribe-to-all/specific-change-events-for-appointment-slots
-func (h *HTTPClient) Unsubscribe(ctx context.Context, feedType string, opts *UnsubscribeOptions) error {
+func (h *HTTPClient) Unsubscribe(ctx context.Context, feedType FeedType, opts *UnsubscribeOptions) error {
 	var form url.Values
 
 	if opts != nil {
@@ -100,3 +114,142 @@ func (h *HTTPClient) Unsubscribe(ctx context.Context, feedType string, opts *Uns
 
 	return nil
 }
+
+// SubscriptionChanges describes the changes EnsureSubscriptions made to a feed's subscription.
+type SubscriptionChanges struct {
+	FeedType     FeedType
+	Subscribed   []string
+	Unsubscribed []string
+	Unchanged    []string
+}
+
+// SubscriptionReport is returned by EnsureSubscriptions.
+type SubscriptionReport struct {
+	Feeds []*SubscriptionChanges
+}
+
+// Changed returns true if any subscription was changed.
+func (s *SubscriptionReport) Changed() bool {
+	for _, feed := range s.Feeds {
+		if len(feed.Subscribed) > 0 || len(feed.Unsubscribed) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnsureSubscriptions converges the practice's subscriptions to desired, which maps each feed to the
+// event names that should be subscribed. Events subscribed to a feed in desired but missing from its
+// event names are unsubscribed; feeds not in desired are left alone. Event names are checked against
+// ListSubscriptionEvents before any change is made to the feed.
+//
+// Feeds are reconciled in order of their FeedType. If an error occurs, the report describes the
+// changes made before it.
+func (h *HTTPClient) EnsureSubscriptions(ctx context.Context, desired map[FeedType][]string) (*SubscriptionReport, error) {
+	feedTypes := make([]FeedType, 0, len(desired))
+	for feedType := range desired {
+		feedTypes = append(feedTypes, feedType)
+	}
+
+	sort.Slice(feedTypes, func(i, j int) bool {
+		return feedTypes[i] < feedTypes[j]
+	})
+
+	report := &SubscriptionReport{}
+
+	for _, feedType := range feedTypes {
+		changes := &SubscriptionChanges{
+			FeedType: feedType,
+		}
+		report.Feeds = append(report.Feeds, changes)
+
+		err := h.ensureSubscription(ctx, feedType, desired[feedType], changes)
+		if err != nil {
+			return report, fmt.Errorf("ensuring %s subscription: %w", feedType, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (h *HTTPClient) ensureSubscription(ctx context.Context, feedType FeedType, desired []string, changes *SubscriptionChanges) error {
+	events, err := h.ListSubscriptionEvents(ctx, feedType)
+	if err != nil {
+		return err
+	}
+
+	desired = uniqueEventNames(desired)
+
+	available := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		available[event.EventName] = struct{}{}
+	}
+
+	for _, eventName := range desired {
+		if _, ok := available[eventName]; !ok {
+			return fmt.Errorf("unknown event %q", eventName)
+		}
+	}
+
+	subscription, err := h.GetSubscription(ctx, feedType)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]struct{}, len(subscription.Subscriptions))
+	for _, event := range subscription.Subscriptions {
+		current[event.EventName] = struct{}{}
+	}
+
+	for _, eventName := range desired {
+		if _, ok := current[eventName]; ok {
+			changes.Unchanged = append(changes.Unchanged, eventName)
+
+			continue
+		}
+
+		err = h.Subscribe(ctx, feedType, &SubscribeOptions{
+			EventName: eventName,
+		})
+		if err != nil {
+			return err
+		}
+
+		changes.Subscribed = append(changes.Subscribed, eventName)
+	}
+
+	for _, event := range subscription.Subscriptions {
+		if slices.Contains(desired, event.EventName) {
+			continue
+		}
+
+		err = h.Unsubscribe(ctx, feedType, &UnsubscribeOptions{
+			EventName: event.EventName,
+		})
+		if err != nil {
+			return err
+		}
+
+		changes.Unsubscribed = append(changes.Unsubscribed, event.EventName)
+	}
+
+	return nil
+}
+
+// uniqueEventNames returns eventNames without duplicates, in order of first appearance.
+func uniqueEventNames(eventNames []string) []string {
+	seen := make(map[string]struct{}, len(eventNames))
+	unique := make([]string, 0, len(eventNames))
+
+	for _, eventName := range eventNames {
+		if _, ok := seen[eventName]; ok {
+			continue
+		}
+
+		seen[eventName] = struct{}{}
+		unique = append(unique, eventName)
+	}
+
+	return unique
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"testing"
 
@@ -21,7 +22,7 @@ func TestHTTPClient_GetSubscription(t *testing.T) {
 	athenaClient, ts := testClient(h)
 	defer ts.Close()
 
-	subscription, err := athenaClient.GetSubscription(context.Background(), "appointments")
+	subscription, err := athenaClient.GetSubscription(context.Background(), FeedTypeAppointments)
 
 	assert.NotNil(subscription)
 	assert.NoError(err)
@@ -38,7 +39,7 @@ func TestHTTPClient_ListSubscriptionEvents(t *testing.T) {
 	athenaClient, ts := testClient(h)
 	defer ts.Close()
 
-	events, err := athenaClient.ListSubscriptionEvents(context.Background(), "appointments")
+	events, err := athenaClient.ListSubscriptionEvents(context.Background(), FeedTypeAppointments)
 
 	assert.Len(events, 11)
 	assert.NoError(err)
@@ -63,7 +64,7 @@ func TestHTTPClient_Subscribe(t *testing.T) {
 	opts := &SubscribeOptions{
 		EventName: "UpdateAppointment",
 	}
-	err := athenaClient.Subscribe(context.Background(), "appointments", opts)
+	err := athenaClient.Subscribe(context.Background(), FeedTypeAppointments, opts)
 
 	assert.NoError(err)
 	assert.True(called)
@@ -88,8 +89,118 @@ func TestHTTPClient_Unsubscribe(t *testing.T) {
 	opts := &UnsubscribeOptions{
 		EventName: "UpdateAppointment",
 	}
-	err := athenaClient.Unsubscribe(context.Background(), "appointments", opts)
+	err := athenaClient.Unsubscribe(context.Background(), FeedTypeAppointments, opts)
 
 	assert.NoError(err)
 	assert.True(called)
 }
+
+func TestHTTPClient_EnsureSubscriptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var subscribed, unsubscribed []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/appointments/changed/subscription/events":
+			_, _ = w.Write([]byte(`{"subscriptions":[{"eventname":"ScheduleAppointment"},{"eventname":"CheckIn"},{"eventname":"CancelAppointment"},{"eventname":"UpdateAppointment"}]}`))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/appointments/changed/subscription":
+			_, _ = w.Write([]byte(`{"status":"ACTIVE","subscriptions":[{"eventname":"ScheduleAppointment"},{"eventname":"CheckIn"}]}`))
+
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			subscribed = append(subscribed, r.Form.Get("eventname"))
+
+		case r.Method == http.MethodDelete:
+			reqBody, _ := io.ReadAll(r.Body)
+			defer func() { _ = r.Body.Close() }()
+
+			form, _ := url.ParseQuery(string(reqBody))
+			unsubscribed = append(unsubscribed, form.Get("eventname"))
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	report, err := athenaClient.EnsureSubscriptions(context.Background(), map[FeedType][]string{
+		FeedTypeAppointments: {"ScheduleAppointment", "CancelAppointment", "UpdateAppointment"},
+	})
+
+	assert.NoError(err)
+	assert.True(report.Changed())
+	assert.Equal([]*SubscriptionChanges{
+		{
+			FeedType:     FeedTypeAppointments,
+			Subscribed:   []string{"CancelAppointment", "UpdateAppointment"},
+			Unsubscribed: []string{"CheckIn"},
+			Unchanged:    []string{"ScheduleAppointment"},
+		},
+	}, report.Feeds)
+	assert.Equal([]string{"CancelAppointment", "UpdateAppointment"}, subscribed)
+	assert.Equal([]string{"CheckIn"}, unsubscribed)
+}
+
+func TestHTTPClient_EnsureSubscriptions_duplicateEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	var subscribed []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/appointments/changed/subscription/events":
+			_, _ = w.Write([]byte(`{"subscriptions":[{"eventname":"ScheduleAppointment"},{"eventname":"CancelAppointment"}]}`))
+
+		case r.Method == http.MethodGet && r.URL.Path == "/appointments/changed/subscription":
+			_, _ = w.Write([]byte(`{"status":"ACTIVE","subscriptions":[{"eventname":"ScheduleAppointment"}]}`))
+
+		case r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			subscribed = append(subscribed, r.Form.Get("eventname"))
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	report, err := athenaClient.EnsureSubscriptions(context.Background(), map[FeedType][]string{
+		FeedTypeAppointments: {"CancelAppointment", "ScheduleAppointment", "CancelAppointment", "ScheduleAppointment"},
+	})
+
+	assert.NoError(err)
+	assert.Equal([]*SubscriptionChanges{
+		{
+			FeedType:   FeedTypeAppointments,
+			Subscribed: []string{"CancelAppointment"},
+			Unchanged:  []string{"ScheduleAppointment"},
+		},
+	}, report.Feeds)
+	assert.Equal([]string{"CancelAppointment"}, subscribed)
+}
+
+func TestHTTPClient_EnsureSubscriptions_unknownEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		_, _ = w.Write([]byte(`{"subscriptions":[{"eventname":"AddProvider"}]}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	report, err := athenaClient.EnsureSubscriptions(context.Background(), map[FeedType][]string{
+		FeedTypeProviders: {"AddProviderr"},
+	})
+
+	assert.ErrorContains(err, `unknown event "AddProviderr"`)
+	assert.False(report.Changed())
+}
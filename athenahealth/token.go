@@ -0,0 +1,178 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tokenRefreshPollInterval is how often fetchToken checks the cache while another instance holds
+// the token refresh lock.
+const tokenRefreshPollInterval = 100 * time.Millisecond
+
+// tokenFetch is an in-flight call to the token provider shared by all requests that found the
+// token cache empty or expired while it was running.
+type tokenFetch struct {
+	done chan struct{}
+
+	token string
+	err   error
+}
+
+// token returns a cached token or, on a cache miss, a freshly provided one. Concurrent misses are
+// coalesced so that only one call to the token provider is in flight per client.
+func (h *HTTPClient) token(ctx context.Context) (string, error) {
+	ctx, span := h.tracer.Start(ctx, "athenahealth.token")
+	defer span.End()
+
+	token, err := h.tokenCacher.Get(ctx)
+	if err == nil {
+		span.SetAttributes(attribute.Bool(attrTokenCacheHit, true))
+
+		return token, nil
+	}
+
+	span.SetAttributes(attribute.Bool(attrTokenCacheHit, false))
+
+	if !errors.Is(err, tokencacher.ErrTokenNotExist) && !errors.Is(err, tokencacher.ErrTokenExpired) {
+		recordSpanError(span, err)
+
+		return "", err
+	}
+
+	h.tokenLock.Lock()
+
+	fetch := h.tokenFetch
+	if fetch == nil {
+		fetch = &tokenFetch{
+			done: make(chan struct{}),
+		}
+		h.tokenFetch = fetch
+
+		// The fetch outlives the request that started it, so it must not be canceled along
+		// with that request's context.
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.requestTimeout)
+
+		go func() {
+			defer cancel()
+
+			fetch.token, fetch.err = h.fetchToken(fetchCtx)
+
+			h.tokenLock.Lock()
+			h.tokenFetch = nil
+			h.tokenLock.Unlock()
+
+			close(fetch.done)
+		}()
+	}
+
+	h.tokenLock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		recordSpanError(span, ctx.Err())
+
+		return "", ctx.Err()
+
+	case <-fetch.done:
+		if fetch.err != nil {
+			recordSpanError(span, fetch.err)
+		}
+
+		return fetch.token, fetch.err
+	}
+}
+
+// fetchToken provides a new token and caches it. If the token cacher is a TokenRefreshLocker,
+// the token is only provided while holding the refresh lock; while another instance holds it,
+// fetchToken polls the cache until that instance has cached its token or the lock expires.
+func (h *HTTPClient) fetchToken(ctx context.Context) (string, error) {
+	locker, _ := h.tokenCacher.(TokenRefreshLocker)
+
+	for {
+		// Another fetch may have populated the cache between our miss and this fetch starting.
+		token, err := h.tokenCacher.Get(ctx)
+		if err == nil {
+			return token, nil
+		}
+
+		if locker == nil {
+			return h.provideToken(ctx, false)
+		}
+
+		unlock, err := locker.LockRefresh(ctx)
+		if errors.Is(err, tokencacher.ErrRefreshLocked) {
+			select {
+			case <-ctx.Done():
+				return "", fmt.Errorf("waiting for token refresh: %w", ctx.Err())
+
+			case <-time.After(tokenRefreshPollInterval):
+			}
+
+			continue
+		}
+
+		if err != nil {
+			h.logger.Error().
+				Err(err).
+				Msg("athenahealth token refresh lock failed, refreshing without it")
+
+			return h.provideToken(ctx, false)
+		}
+
+		// The previous lock holder may have cached a token just before we acquired the lock.
+		token, err = h.tokenCacher.Get(ctx)
+		if err != nil {
+			token, err = h.provideToken(ctx, false)
+		}
+
+		unlockErr := unlock(ctx)
+		if unlockErr != nil {
+			h.logger.Error().
+				Err(unlockErr).
+				Msg("athenahealth token refresh unlock failed")
+		}
+
+		return token, err
+	}
+}
+
+// provideToken provides a new token and caches it. background is reported to StatsV2 and is set
+// for refreshes started by StartTokenRefresh rather than by a request.
+func (h *HTTPClient) provideToken(ctx context.Context, background bool) (string, error) {
+	start := time.Now()
+
+	token, expiresAt, err := h.tokenProvider.Provide(ctx)
+
+	h.tokenRefreshed(&stats.TokenRefreshStat{
+		PracticeID: h.practiceID,
+		Preview:    h.preview,
+		Background: background,
+		Duration:   time.Since(start),
+		Err:        err,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	// Remove 1 minute from the expiration time to create a buffer for clock
+	// skew. Tokens that are rejected anyway are invalidated on a 401.
+	expiresAt = expiresAt.Add(-1 * time.Minute)
+
+	err = h.tokenCacher.Set(ctx, token, expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	h.tokenLock.Lock()
+	h.tokenExpiresAt = expiresAt
+	h.tokenLock.Unlock()
+
+	return token, nil
+}
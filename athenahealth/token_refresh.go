@@ -0,0 +1,218 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+)
+
+const (
+	defaultTokenRefreshBaseDelay = time.Second
+	defaultTokenRefreshMaxDelay  = time.Minute
+)
+
+// tokenRefresher is a running background token refresh.
+type tokenRefresher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartTokenRefresh starts renewing the token in the background window before it expires, so
+// requests do not wait for the token provider when the cached token expires. Failed refreshes are
+// logged, reported to Stats that implement StatsV2 and retried with exponential backoff;
+// requests fall back to refreshing the token themselves if the token expires in the meantime.
+//
+// If window is more than half of the token's remaining lifetime, the token is renewed halfway
+// through its remaining lifetime instead. Before renewing, the cache is checked for a token
+// another instance has already renewed, if the token cacher is a tokencacher.ExpiryGetter.
+//
+// The expiration of a token this client did not provide itself is unknown, so the first refresh
+// happens immediately (and only checks the cache if it can).
+//
+// Call StartTokenRefresh after configuring the client, and Close to stop the refresh. Calling it
+// again while a refresh is running has no effect.
+func (h *HTTPClient) StartTokenRefresh(window time.Duration) {
+	h.startTokenRefresh(window, &RetryPolicy{
+		BaseDelay: defaultTokenRefreshBaseDelay,
+		MaxDelay:  defaultTokenRefreshMaxDelay,
+	})
+}
+
+func (h *HTTPClient) startTokenRefresh(window time.Duration, backoff *RetryPolicy) {
+	h.tokenLock.Lock()
+	defer h.tokenLock.Unlock()
+
+	if h.tokenRefresher != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &tokenRefresher{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	h.tokenRefresher = r
+
+	go func() {
+		defer close(r.done)
+
+		h.runTokenRefresh(ctx, window, backoff)
+	}()
+}
+
+// Close stops the background token refresh started by StartTokenRefresh and waits for it to
+// return. It is safe to call Close more than once, or without StartTokenRefresh.
+func (h *HTTPClient) Close() error {
+	h.tokenLock.Lock()
+	r := h.tokenRefresher
+	h.tokenRefresher = nil
+	h.tokenLock.Unlock()
+
+	if r != nil {
+		r.cancel()
+		<-r.done
+	}
+
+	return nil
+}
+
+func (h *HTTPClient) runTokenRefresh(ctx context.Context, window time.Duration, backoff *RetryPolicy) {
+	failures := 0
+
+	for {
+		var delay time.Duration
+
+		if failures > 0 {
+			delay = backoff.backoff(failures)
+		} else {
+			h.tokenLock.Lock()
+			expiresAt := h.tokenExpiresAt
+			h.tokenLock.Unlock()
+
+			if !expiresAt.IsZero() {
+				delay = tokenRefreshDelay(expiresAt, window)
+
+				// A token that expires within the clock skew buffer as soon as it is provided
+				// cannot be renewed ahead of time, so do not renew it in a loop.
+				if delay <= 0 {
+					delay = backoff.MaxDelay
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(delay):
+		}
+
+		err := h.refreshToken(ctx, window)
+		if err == nil {
+			failures = 0
+
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		failures++
+
+		h.logger.Error().
+			Int("failures", failures).
+			Err(err).
+			Msg("athenahealth background token refresh failed")
+	}
+}
+
+// tokenRefreshDelay returns how long to wait before renewing a token that expires at expiresAt:
+// until window before it expires, but no earlier than halfway through its remaining lifetime.
+func tokenRefreshDelay(expiresAt time.Time, window time.Duration) time.Duration {
+	remaining := time.Until(expiresAt)
+
+	return max(remaining-window, remaining/2)
+}
+
+// refreshToken provides and caches a new token whether or not the cached one has expired, unless
+// another instance has already renewed the cached token. If another instance holds the token
+// refresh lock, it waits for the lock to be released first.
+func (h *HTTPClient) refreshToken(ctx context.Context, window time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, h.requestTimeout)
+	defer cancel()
+
+	locker, ok := h.tokenCacher.(TokenRefreshLocker)
+	if !ok {
+		if h.cachedTokenRenewed(ctx, window) {
+			return nil
+		}
+
+		_, err := h.provideToken(ctx, true)
+
+		return err
+	}
+
+	for {
+		unlock, err := locker.LockRefresh(ctx)
+		if errors.Is(err, tokencacher.ErrRefreshLocked) {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+
+			case <-time.After(tokenRefreshPollInterval):
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// The previous lock holder may have just renewed the token.
+		if !h.cachedTokenRenewed(ctx, window) {
+			_, err = h.provideToken(ctx, true)
+		}
+
+		unlockErr := unlock(ctx)
+		if unlockErr != nil {
+			h.logger.Error().
+				Err(unlockErr).
+				Msg("athenahealth token refresh unlock failed")
+		}
+
+		return err
+	}
+}
+
+// cachedTokenRenewed reports whether the cached token was renewed by another instance since this
+// client last saw it, or does not need renewing within window. If so, its expiration is recorded
+// so the next refresh is scheduled from it. It reports false if the token cacher cannot report the
+// expiration.
+func (h *HTTPClient) cachedTokenRenewed(ctx context.Context, window time.Duration) bool {
+	getter, ok := h.tokenCacher.(tokencacher.ExpiryGetter)
+	if !ok {
+		return false
+	}
+
+	_, expiresAt, err := getter.GetWithExpiry(ctx)
+	if err != nil || expiresAt.IsZero() {
+		return false
+	}
+
+	h.tokenLock.Lock()
+	defer h.tokenLock.Unlock()
+
+	renewed := !h.tokenExpiresAt.IsZero() && expiresAt.After(h.tokenExpiresAt)
+	if !renewed && time.Until(expiresAt) <= window {
+		return false
+	}
+
+	h.tokenExpiresAt = expiresAt
+
+	return true
+}
@@ -0,0 +1,277 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// expiringTokenProvider provides tokens that expire after lifetime (plus the minute HTTPClient
+// subtracts for clock skew). The first failures calls fail.
+type expiringTokenProvider struct {
+	lifetime time.Duration
+	failures int32
+
+	calls int32
+}
+
+func (e *expiringTokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	calls := atomic.AddInt32(&e.calls, 1)
+
+	if calls <= e.failures {
+		return "", time.Time{}, errors.New("provider error")
+	}
+
+	return testToken, time.Now().Add(time.Minute + e.lifetime), nil
+}
+
+// tokenRefreshStats counts failed background token refreshes.
+type tokenRefreshStats struct {
+	testStatsV2
+
+	errors int32
+}
+
+func (t *tokenRefreshStats) TokenRefreshed(stat *stats.TokenRefreshStat) error {
+	if stat.Background && stat.Err != nil {
+		atomic.AddInt32(&t.errors, 1)
+	}
+
+	return nil
+}
+
+func testBackoff() *RetryPolicy {
+	return &RetryPolicy{
+		BaseDelay: time.Millisecond,
+		MaxDelay:  time.Millisecond,
+	}
+}
+
+func TestHTTPClient_StartTokenRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &expiringTokenProvider{lifetime: 300 * time.Millisecond}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	_, err := athenaClient.token(context.Background())
+	assert.NoError(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+
+	athenaClient.startTokenRefresh(200*time.Millisecond, testBackoff())
+	defer func() { _ = athenaClient.Close() }()
+
+	// The token is renewed 200ms before it expires, i.e. after about 100ms.
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+
+	assert.Eventually(func() bool {
+		return atomic.LoadInt32(&provider.calls) == 2
+	}, 200*time.Millisecond, 5*time.Millisecond)
+
+	// Requests never find the cache empty.
+	for range 5 {
+		_, err = athenaClient.tokenCacher.Get(context.Background())
+		assert.NoError(err)
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestHTTPClient_StartTokenRefresh_unknownExpiration(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &expiringTokenProvider{lifetime: time.Hour}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	athenaClient.startTokenRefresh(time.Minute, testBackoff())
+	defer func() { _ = athenaClient.Close() }()
+
+	assert.Eventually(func() bool {
+		return atomic.LoadInt32(&provider.calls) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	token, err := athenaClient.tokenCacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+}
+
+func TestHTTPClient_StartTokenRefresh_errors(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &expiringTokenProvider{lifetime: time.Hour, failures: 3}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	refreshStats := &tokenRefreshStats{}
+	athenaClient.WithStats(refreshStats)
+
+	athenaClient.startTokenRefresh(time.Minute, testBackoff())
+	defer func() { _ = athenaClient.Close() }()
+
+	assert.Eventually(func() bool {
+		return atomic.LoadInt32(&provider.calls) == 4
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(int32(3), atomic.LoadInt32(&refreshStats.errors))
+
+	token, err := athenaClient.tokenCacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+}
+
+func TestHTTPClient_StartTokenRefresh_windowExceedsLifetime(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &expiringTokenProvider{lifetime: time.Hour}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	// The token is renewed halfway through its lifetime rather than continuously.
+	athenaClient.startTokenRefresh(2*time.Hour, testBackoff())
+	defer func() { _ = athenaClient.Close() }()
+
+	time.Sleep(200 * time.Millisecond)
+
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_StartTokenRefresh_expiresImmediately(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	// The token expires within the clock skew buffer as soon as it is provided.
+	provider := &expiringTokenProvider{lifetime: 0}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	athenaClient.startTokenRefresh(time.Minute, &RetryPolicy{
+		BaseDelay: time.Millisecond,
+		MaxDelay:  time.Hour,
+	})
+	defer func() { _ = athenaClient.Close() }()
+
+	time.Sleep(200 * time.Millisecond)
+
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_StartTokenRefresh_sharedCache(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	newClient := func(provider TokenProvider) *HTTPClient {
+		return NewHTTPClient(&http.Client{}, testPracticeID, testAPIKey, testAPISecret).
+			WithTokenProvider(provider).
+			WithTokenCacher(tokencacher.NewRedis(redis.NewClient(&redis.Options{
+				Addr: s.Addr(),
+			}), "").WithRefreshLock(time.Minute))
+	}
+
+	providerA := &expiringTokenProvider{lifetime: time.Hour}
+	clientA := newClient(providerA)
+
+	_, err = clientA.token(context.Background())
+	assert.NoError(err)
+
+	// B finds the token A cached instead of providing its own.
+	providerB := &expiringTokenProvider{lifetime: time.Hour}
+	clientB := newClient(providerB)
+
+	clientB.startTokenRefresh(time.Minute, testBackoff())
+	defer func() { _ = clientB.Close() }()
+
+	assert.Eventually(func() bool {
+		clientB.tokenLock.Lock()
+		defer clientB.tokenLock.Unlock()
+
+		return !clientB.tokenExpiresAt.IsZero()
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(int32(0), atomic.LoadInt32(&providerB.calls))
+}
+
+func TestHTTPClient_refreshToken_renewedByOtherInstance(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := tokencacher.NewDefault()
+
+	providerA := &expiringTokenProvider{lifetime: time.Hour}
+	clientA := NewHTTPClient(&http.Client{}, testPracticeID, testAPIKey, testAPISecret).
+		WithTokenProvider(providerA).
+		WithTokenCacher(cacher)
+
+	providerB := &expiringTokenProvider{lifetime: time.Minute}
+	clientB := NewHTTPClient(&http.Client{}, testPracticeID, testAPIKey, testAPISecret).
+		WithTokenProvider(providerB).
+		WithTokenCacher(cacher)
+
+	assert.NoError(clientB.refreshToken(context.Background(), time.Hour))
+	assert.Equal(int32(1), atomic.LoadInt32(&providerB.calls))
+
+	assert.NoError(clientA.refreshToken(context.Background(), time.Hour))
+	assert.Equal(int32(1), atomic.LoadInt32(&providerA.calls))
+
+	// A renewed the token since B last saw it, so B does not renew it again even though it
+	// expires within the window.
+	assert.NoError(clientB.refreshToken(context.Background(), 2*time.Hour))
+	assert.Equal(int32(1), atomic.LoadInt32(&providerB.calls))
+	assert.Equal(clientA.tokenExpiresAt, clientB.tokenExpiresAt)
+}
+
+func TestHTTPClient_Close(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &expiringTokenProvider{lifetime: 0, failures: 1000}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	// Closing without a refresh running is a no-op.
+	assert.NoError(athenaClient.Close())
+
+	athenaClient.startTokenRefresh(time.Minute, testBackoff())
+	athenaClient.startTokenRefresh(time.Minute, testBackoff())
+
+	time.Sleep(20 * time.Millisecond)
+
+	assert.NoError(athenaClient.Close())
+	assert.NoError(athenaClient.Close())
+
+	calls := atomic.LoadInt32(&provider.calls)
+	assert.NotZero(calls)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(calls, atomic.LoadInt32(&provider.calls))
+}
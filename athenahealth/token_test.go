@@ -0,0 +1,259 @@
+package athenahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTokenProvider struct {
+	calls int32
+	delay time.Duration
+	err   error
+}
+
+func (c *countingTokenProvider) Provide(ctx context.Context) (string, time.Time, error) {
+	atomic.AddInt32(&c.calls, 1)
+
+	select {
+	case <-ctx.Done():
+		return "", time.Time{}, ctx.Err()
+	case <-time.After(c.delay):
+	}
+
+	if c.err != nil {
+		return "", time.Time{}, c.err
+	}
+
+	return testToken, time.Now().Add(time.Hour), nil
+}
+
+func TestHTTPClient_token_singleFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &countingTokenProvider{delay: 50 * time.Millisecond}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := athenaClient.token(context.Background())
+			assert.NoError(err)
+			assert.Equal(testToken, token)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_token_error(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	providerErr := errors.New("provider error")
+	provider := &countingTokenProvider{err: providerErr}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	_, err := athenaClient.token(context.Background())
+	assert.ErrorIs(err, providerErr)
+
+	// A failed fetch is not shared with later callers.
+	_, err = athenaClient.token(context.Background())
+	assert.ErrorIs(err, providerErr)
+	assert.Equal(int32(2), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_token_waiterCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &countingTokenProvider{delay: 50 * time.Millisecond}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewDefault())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := athenaClient.token(ctx)
+	assert.ErrorIs(err, context.Canceled)
+
+	// The fetch started by the canceled request still completes for other callers.
+	token, err := athenaClient.token(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_token_refreshLock(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	provider := &countingTokenProvider{delay: 200 * time.Millisecond}
+
+	// Each client stands in for an instance with its own in-process single flight.
+	var clients []*HTTPClient
+
+	for range 3 {
+		athenaClient, ts := testClient(nil)
+		defer ts.Close()
+
+		athenaClient.WithTokenProvider(provider)
+		athenaClient.WithTokenCacher(tokencacher.NewRedis(redisClient, "").WithRefreshLock(5 * time.Second))
+
+		clients = append(clients, athenaClient)
+	}
+
+	var wg sync.WaitGroup
+
+	for _, athenaClient := range clients {
+		wg.Go(func() {
+			token, err := athenaClient.token(context.Background())
+			assert.NoError(err)
+			assert.Equal(testToken, token)
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+	assert.False(s.Exists(tokencacher.RedisDefaultKey + ":refresh_lock"))
+}
+
+func TestHTTPClient_token_refreshLockHolderDied(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &countingTokenProvider{}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(tokencacher.NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithRefreshLock(5 * time.Second))
+
+	// A lock left behind by an instance that died before caching a token.
+	lockKey := tokencacher.RedisDefaultKey + ":refresh_lock"
+	_ = s.Set(lockKey, "dead")
+
+	go func() {
+		time.Sleep(250 * time.Millisecond)
+
+		// The lock expires.
+		s.Del(lockKey)
+	}()
+
+	start := time.Now()
+
+	token, err := athenaClient.token(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+	assert.GreaterOrEqual(time.Since(start), 250*time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+type failingLockTokenCacher struct {
+	*tokencacher.Default
+}
+
+func (f *failingLockTokenCacher) LockRefresh(ctx context.Context) (func(context.Context) error, error) {
+	return nil, errors.New("lock error")
+}
+
+func TestHTTPClient_token_refreshLockError(t *testing.T) {
+	assert := assert.New(t)
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+
+	provider := &countingTokenProvider{}
+	athenaClient.WithTokenProvider(provider)
+	athenaClient.WithTokenCacher(&failingLockTokenCacher{tokencacher.NewDefault()})
+
+	token, err := athenaClient.token(context.Background())
+	assert.NoError(err)
+	assert.Equal(testToken, token)
+	assert.Equal(int32(1), atomic.LoadInt32(&provider.calls))
+}
+
+func TestHTTPClient_request_concurrentRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	const concurrency = 10
+
+	var inFlight, maxInFlight int32
+	rateLimiter := &testRateLimiter{}
+	rateLimiter.AllowedFunc = func(preview bool) (time.Duration, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		return 0, nil
+	}
+
+	athenaClient, ts := testClient(nil)
+	defer ts.Close()
+	athenaClient.WithRateLimiter(rateLimiter)
+
+	var wg sync.WaitGroup
+
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := athenaClient.request(context.Background(), http.MethodGet, "/", nil, nil, nil)
+			assert.NoError(err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Greater(atomic.LoadInt32(&maxInFlight), int32(1))
+}
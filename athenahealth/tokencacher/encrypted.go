@@ -0,0 +1,171 @@
+package tokencacher
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// encryptedPrefix marks tokens encrypted by Encrypted, so the format can change in the future.
+const encryptedPrefix = "aesgcm1:"
+
+// refreshLocker matches athenahealth.TokenRefreshLocker.
+type refreshLocker interface {
+	LockRefresh(ctx context.Context) (func(context.Context) error, error)
+}
+
+// Encrypted is a TokenCacher that encrypts tokens with AES-GCM before storing them in another
+// TokenCacher, such as File or Redis, so the bearer token is never stored in plaintext.
+//
+// Tokens are encrypted with the current key of a KeyProvider and decrypted with any of its keys,
+// so keys can be rotated without discarding cached tokens. A cached token that cannot be
+// decrypted, such as a plaintext token cached before encryption was enabled, is treated as missing
+// and replaced on the next refresh.
+type Encrypted struct {
+	cacher TokenCacher
+	keys   KeyProvider
+}
+
+// TokenCacher matches athenahealth.TokenCacher.
+type TokenCacher interface {
+	Get(context.Context) (string, error)
+	Set(context.Context, string, time.Time) error
+	Invalidate(context.Context) error
+}
+
+func NewEncrypted(cacher TokenCacher, keys KeyProvider) *Encrypted {
+	if cacher == nil {
+		panic("cacher is nil")
+	}
+
+	if keys == nil {
+		panic("keys is nil")
+	}
+
+	return &Encrypted{
+		cacher: cacher,
+		keys:   keys,
+	}
+}
+
+func (e *Encrypted) Get(ctx context.Context) (string, error) {
+	token, _, err := e.GetWithExpiry(ctx)
+
+	return token, err
+}
+
+// GetWithExpiry returns the decrypted token and, if the wrapped cacher is an ExpiryGetter, when it
+// expires.
+func (e *Encrypted) GetWithExpiry(ctx context.Context) (string, time.Time, error) {
+	ciphertext, expiresAt, err := getWithExpiry(ctx, e.cacher)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	keys, err := e.keys.Keys(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("getting keys: %w", err)
+	}
+
+	token, err := decrypt(keys, ciphertext)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: %s", ErrTokenNotExist, err)
+	}
+
+	return token, expiresAt, nil
+}
+
+func (e *Encrypted) Set(ctx context.Context, token string, expiresAt time.Time) error {
+	keys, err := e.keys.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("getting keys: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return errors.New("no keys")
+	}
+
+	ciphertext, err := encrypt(keys[0], token)
+	if err != nil {
+		return err
+	}
+
+	return e.cacher.Set(ctx, ciphertext, expiresAt)
+}
+
+func (e *Encrypted) Invalidate(ctx context.Context) error {
+	return e.cacher.Invalidate(ctx)
+}
+
+// LockRefresh implements athenahealth.TokenRefreshLocker by deferring to the wrapped cacher, if it
+// implements it.
+func (e *Encrypted) LockRefresh(ctx context.Context) (func(context.Context) error, error) {
+	if locker, ok := e.cacher.(refreshLocker); ok {
+		return locker.LockRefresh(ctx)
+	}
+
+	return func(context.Context) error { return nil }, nil
+}
+
+func encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decrypt(keys [][]byte, ciphertext string) (string, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, encryptedPrefix)
+	if !ok {
+		return "", errors.New("token is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding token: %s", err)
+	}
+
+	for _, key := range keys {
+		gcm, err := newGCM(key)
+		if err != nil {
+			return "", err
+		}
+
+		if len(sealed) < gcm.NonceSize() {
+			return "", errors.New("token is too short")
+		}
+
+		plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+		if err == nil {
+			return string(plaintext), nil
+		}
+	}
+
+	return "", errors.New("token cannot be decrypted with any key")
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
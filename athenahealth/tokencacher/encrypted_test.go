@@ -0,0 +1,191 @@
+package tokencacher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testKeyA = bytes.Repeat([]byte{'a'}, 32)
+	testKeyB = bytes.Repeat([]byte{'b'}, 32)
+)
+
+func TestEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	inner := NewDefault()
+	cacher := NewEncrypted(inner, NewStaticKeys(testKeyA))
+
+	_, err := cacher.Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	stored, err := inner.Get(context.Background())
+	assert.NoError(err)
+	assert.NotContains(stored, "foo")
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+
+	// Every Set uses a fresh nonce.
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	restored, _ := inner.Get(context.Background())
+	assert.NotEqual(stored, restored)
+
+	err = cacher.Invalidate(context.Background())
+	assert.NoError(err)
+
+	_, err = inner.Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+}
+
+func TestEncrypted_expired(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncrypted(NewDefault(), NewStaticKeys(testKeyA))
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(-time.Minute))
+	assert.NoError(err)
+
+	_, err = cacher.Get(context.Background())
+	assert.ErrorIs(err, ErrTokenExpired)
+}
+
+func TestEncrypted_keyRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	inner := NewDefault()
+
+	err := NewEncrypted(inner, NewStaticKeys(testKeyA)).Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	// B is the current key, A the previous one.
+	rotated := NewEncrypted(inner, NewStaticKeys(testKeyB, testKeyA))
+
+	token, err := rotated.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+
+	err = rotated.Set(context.Background(), "bar", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	// Once A is retired, tokens encrypted with B are still readable.
+	token, err = NewEncrypted(inner, NewStaticKeys(testKeyB)).Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("bar", token)
+
+	// A token encrypted with an unknown key is treated as missing.
+	_, err = NewEncrypted(inner, NewStaticKeys(testKeyA)).Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+}
+
+func TestEncrypted_plaintext(t *testing.T) {
+	assert := assert.New(t)
+
+	inner := NewDefault()
+	_ = inner.Set(context.Background(), "plaintext", time.Now().Add(time.Minute))
+
+	_, err := NewEncrypted(inner, NewStaticKeys(testKeyA)).Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+}
+
+func TestEncrypted_invalidKey(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncrypted(NewDefault(), NewStaticKeys([]byte("short")))
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.Error(err)
+}
+
+type errKeys struct{}
+
+func (e *errKeys) Keys(ctx context.Context) ([][]byte, error) {
+	return nil, errors.New("keys error")
+}
+
+func TestEncrypted_keysError(t *testing.T) {
+	assert := assert.New(t)
+
+	inner := NewDefault()
+	_ = inner.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+
+	cacher := NewEncrypted(inner, &errKeys{})
+
+	_, err := cacher.Get(context.Background())
+	assert.ErrorContains(err, "keys error")
+	assert.False(errors.Is(err, ErrTokenNotExist))
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.ErrorContains(err, "keys error")
+}
+
+func TestEncrypted_file(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewEncrypted(NewFile(filepath.Join(t.TempDir(), "token.json")), NewStaticKeys(testKeyA))
+
+	err := cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+}
+
+func TestEncrypted_redis(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	inner := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithRefreshLock(time.Minute)
+
+	cacher := NewEncrypted(inner, NewStaticKeys(testKeyA))
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	stored, _ := s.Get(RedisDefaultKey)
+	assert.NotContains(stored, "foo")
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+
+	// The refresh lock of the wrapped cacher is used.
+	unlock, err := cacher.LockRefresh(context.Background())
+	assert.NoError(err)
+	assert.True(s.Exists(RedisDefaultKey + redisRefreshLockSuffix))
+	assert.NoError(unlock(context.Background()))
+}
+
+func TestNewEncrypted_nil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewEncrypted(nil, NewStaticKeys(testKeyA))
+	})
+
+	assert.Panics(func() {
+		NewEncrypted(NewDefault(), nil)
+	})
+}
@@ -4,3 +4,4 @@ import "errors"
 
 var ErrTokenNotExist = errors.New("token does not exist")
 var ErrTokenExpired = errors.New("token expired")
+var ErrRefreshLocked = errors.New("token refresh locked by another instance")
@@ -31,6 +31,13 @@ func NewFile(path string) *File {
 }
 
 func (f *File) Get(ctx context.Context) (string, error) {
+	token, _, err := f.GetWithExpiry(ctx)
+
+	return token, err
+}
+
+// GetWithExpiry returns the cached token and when it expires.
+func (f *File) GetWithExpiry(ctx context.Context) (string, time.Time, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
@@ -38,30 +45,30 @@ func (f *File) Get(ctx context.Context) (string, error) {
 	if os.IsNotExist(err) {
 		err = os.WriteFile(f.path, nil, 0600)
 		if err != nil {
-			return "", err
+			return "", time.Time{}, err
 		}
 	}
 
 	contents, err := os.ReadFile(f.path)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	if len(contents) == 0 {
-		return "", ErrTokenNotExist
+		return "", time.Time{}, ErrTokenNotExist
 	}
 
 	c := &fileCache{}
 	err = json.Unmarshal(contents, c)
 	if err != nil {
-		return "", fmt.Errorf("error unmarshaling token: %s", err)
+		return "", time.Time{}, fmt.Errorf("error unmarshaling token: %s", err)
 	}
 
 	if time.Now().After(c.ExpiresAt) {
-		return "", ErrTokenExpired
+		return "", time.Time{}, ErrTokenExpired
 	}
 
-	return c.Token, nil
+	return c.Token, c.ExpiresAt, nil
 }
 
 func (f *File) Set(ctx context.Context, token string, expiresAt time.Time) error {
@@ -85,3 +92,15 @@ func (f *File) Set(ctx context.Context, token string, expiresAt time.Time) error
 
 	return nil
 }
+
+func (f *File) Invalidate(ctx context.Context) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	err := os.WriteFile(f.path, nil, 0600)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -104,3 +104,26 @@ func TestFile_Set(t *testing.T) {
 	assert.Equal(token, c.Token)
 	assert.True(expiresAt.Equal(c.ExpiresAt))
 }
+
+func TestFile_Invalidate(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.CreateTemp("", "go-athenahealth_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	cacher := NewFile(file.Name())
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute*1))
+	assert.NoError(err)
+
+	err = cacher.Invalidate(context.Background())
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
@@ -0,0 +1,128 @@
+package tokencacher
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyProvider provides the AES keys used by Encrypted.
+type KeyProvider interface {
+	// Keys returns the current key, which is used to encrypt tokens, followed by any previous keys,
+	// which are only used to decrypt tokens cached before a key rotation. Keys must be 16, 24 or 32
+	// bytes long (AES-128, AES-192 or AES-256).
+	Keys(ctx context.Context) ([][]byte, error)
+}
+
+// StaticKeys is a KeyProvider for keys known at startup.
+type StaticKeys struct {
+	keys [][]byte
+}
+
+// NewStaticKeys returns a KeyProvider for current and any previous keys.
+func NewStaticKeys(current []byte, previous ...[]byte) *StaticKeys {
+	if len(current) == 0 {
+		panic("current key required")
+	}
+
+	return &StaticKeys{
+		keys: append([][]byte{current}, previous...),
+	}
+}
+
+func (s *StaticKeys) Keys(ctx context.Context) ([][]byte, error) {
+	return s.keys, nil
+}
+
+// FileKeys is a KeyProvider that reads base64-encoded keys, one per line, from a file on every
+// call, so keys can be rotated (e.g. by a mounted secret) without a restart. The first key is the
+// current key. Blank lines and lines starting with # are ignored.
+type FileKeys struct {
+	path string
+}
+
+func NewFileKeys(path string) *FileKeys {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	return &FileKeys{
+		path: path,
+	}
+}
+
+func (f *FileKeys) Keys(ctx context.Context) ([][]byte, error) {
+	contents, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded []string
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		encoded = append(encoded, line)
+	}
+
+	return decodeKeys(encoded)
+}
+
+// EnvKeys is a KeyProvider that reads comma-separated base64-encoded keys from an environment
+// variable. The first key is the current key.
+type EnvKeys struct {
+	name string
+}
+
+func NewEnvKeys(name string) *EnvKeys {
+	if len(name) == 0 {
+		panic("name required")
+	}
+
+	return &EnvKeys{
+		name: name,
+	}
+}
+
+func (e *EnvKeys) Keys(ctx context.Context) ([][]byte, error) {
+	v, ok := os.LookupEnv(e.name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", e.name)
+	}
+
+	var encoded []string
+
+	for _, k := range strings.Split(v, ",") {
+		k = strings.TrimSpace(k)
+		if len(k) > 0 {
+			encoded = append(encoded, k)
+		}
+	}
+
+	return decodeKeys(encoded)
+}
+
+func decodeKeys(encoded []string) ([][]byte, error) {
+	if len(encoded) == 0 {
+		return nil, errors.New("no keys")
+	}
+
+	keys := make([][]byte, 0, len(encoded))
+
+	for i, e := range encoded {
+		key, err := base64.StdEncoding.DecodeString(e)
+		if err != nil {
+			return nil, fmt.Errorf("decoding key %d: %w", i, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
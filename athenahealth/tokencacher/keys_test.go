@@ -0,0 +1,62 @@
+package tokencacher
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	keys, err := NewStaticKeys(testKeyA, testKeyB).Keys(context.Background())
+	assert.NoError(err)
+	assert.Equal([][]byte{testKeyA, testKeyB}, keys)
+
+	assert.Panics(func() {
+		NewStaticKeys(nil)
+	})
+}
+
+func TestFileKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "keys")
+
+	contents := "# current\n" + base64.StdEncoding.EncodeToString(testKeyB) + "\n\n" + base64.StdEncoding.EncodeToString(testKeyA) + "\n"
+	assert.NoError(os.WriteFile(path, []byte(contents), 0600))
+
+	keys, err := NewFileKeys(path).Keys(context.Background())
+	assert.NoError(err)
+	assert.Equal([][]byte{testKeyB, testKeyA}, keys)
+
+	assert.NoError(os.WriteFile(path, []byte("not base64!"), 0600))
+
+	_, err = NewFileKeys(path).Keys(context.Background())
+	assert.Error(err)
+
+	assert.NoError(os.WriteFile(path, nil, 0600))
+
+	_, err = NewFileKeys(path).Keys(context.Background())
+	assert.Error(err)
+
+	_, err = NewFileKeys(filepath.Join(t.TempDir(), "missing")).Keys(context.Background())
+	assert.Error(err)
+}
+
+func TestEnvKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("ATHENA_TOKEN_KEYS", base64.StdEncoding.EncodeToString(testKeyA)+", "+base64.StdEncoding.EncodeToString(testKeyB))
+
+	keys, err := NewEnvKeys("ATHENA_TOKEN_KEYS").Keys(context.Background())
+	assert.NoError(err)
+	assert.Equal([][]byte{testKeyA, testKeyB}, keys)
+
+	_, err = NewEnvKeys("ATHENA_TOKEN_KEYS_MISSING").Keys(context.Background())
+	assert.Error(err)
+}
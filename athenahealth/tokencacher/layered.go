@@ -0,0 +1,127 @@
+package tokencacher
+
+import (
+	"context"
+	"time"
+)
+
+const defaultLayeredMemoryTTL = time.Minute
+
+// ExpiryGetter is implemented by TokenCachers that can report when the cached token expires.
+type ExpiryGetter interface {
+	GetWithExpiry(ctx context.Context) (string, time.Time, error)
+}
+
+// getWithExpiry returns the token cached by cacher and, if cacher is an ExpiryGetter, when it
+// expires. The expiry is zero if it is unknown.
+func getWithExpiry(ctx context.Context, cacher TokenCacher) (string, time.Time, error) {
+	if g, ok := cacher.(ExpiryGetter); ok {
+		return g.GetWithExpiry(ctx)
+	}
+
+	token, err := cacher.Get(ctx)
+
+	return token, time.Time{}, err
+}
+
+// Layered is a TokenCacher that keeps the token in memory in front of a shared cacher, such as
+// Redis, so most requests do not need a round trip to the shared store. Get only falls through to
+// the shared cacher when the in-memory token is missing or expired; Set and Invalidate write to
+// both tiers.
+//
+// A token read from the shared cacher is kept in memory until it expires if the shared cacher is an
+// ExpiryGetter (Redis, File and Encrypted over either are), or for the memory TTL otherwise.
+type Layered struct {
+	memory *Default
+	shared TokenCacher
+
+	memoryTTL time.Duration
+}
+
+func NewLayered(shared TokenCacher) *Layered {
+	if shared == nil {
+		panic("shared is nil")
+	}
+
+	return &Layered{
+		memory: NewDefault(),
+		shared: shared,
+
+		memoryTTL: defaultLayeredMemoryTTL,
+	}
+}
+
+// WithMemoryTTL sets how long a token read from a shared cacher that cannot report its expiry is
+// kept in memory. It defaults to 1 minute.
+func (l *Layered) WithMemoryTTL(ttl time.Duration) *Layered {
+	l.memoryTTL = ttl
+
+	return l
+}
+
+func (l *Layered) Get(ctx context.Context) (string, error) {
+	token, _, err := l.GetWithExpiry(ctx)
+
+	return token, err
+}
+
+// GetWithExpiry returns the token and when it expires. The expiration of a token read from a
+// shared cacher that cannot report it is unknown, so it is zero.
+func (l *Layered) GetWithExpiry(ctx context.Context) (string, time.Time, error) {
+	token, expiresAt, err := l.memory.GetWithExpiry(ctx)
+	if err == nil {
+		return token, expiresAt, nil
+	}
+
+	token, expiresAt, err = getWithExpiry(ctx, l.shared)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	memoryExpiresAt := expiresAt
+	if memoryExpiresAt.IsZero() {
+		memoryExpiresAt = time.Now().Add(l.memoryTTL)
+	}
+
+	// The memory tier cannot fail.
+	_ = l.memory.Set(ctx, token, memoryExpiresAt)
+
+	return token, expiresAt, nil
+}
+
+func (l *Layered) Set(ctx context.Context, token string, expiresAt time.Time) error {
+	err := l.shared.Set(ctx, token, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	return l.memory.Set(ctx, token, expiresAt)
+}
+
+func (l *Layered) Invalidate(ctx context.Context) error {
+	// Invalidate memory first so a failure of the shared tier does not leave a rejected token in
+	// memory.
+	_ = l.memory.Invalidate(ctx)
+
+	return l.shared.Invalidate(ctx)
+}
+
+// LockRefresh implements athenahealth.TokenRefreshLocker by deferring to the shared cacher, if it
+// implements it. Once the lock is acquired, the memory tier is cleared so the lock holder reads a
+// token the previous holder may have just cached in the shared tier.
+func (l *Layered) LockRefresh(ctx context.Context) (func(context.Context) error, error) {
+	unlock := func(context.Context) error { return nil }
+
+	if locker, ok := l.shared.(refreshLocker); ok {
+		var err error
+
+		unlock, err = locker.LockRefresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_ = l.memory.Invalidate(ctx)
+
+	return unlock, nil
+}
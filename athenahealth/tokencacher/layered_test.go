@@ -0,0 +1,217 @@
+package tokencacher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingCacher struct {
+	TokenCacher
+
+	gets int
+}
+
+func (c *countingCacher) Get(ctx context.Context) (string, error) {
+	c.gets++
+
+	return c.TokenCacher.Get(ctx)
+}
+
+func TestLayered_Get(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := &countingCacher{TokenCacher: NewDefault()}
+	_ = shared.Set(context.Background(), "foo", time.Now().Add(time.Hour))
+
+	cacher := NewLayered(shared)
+
+	for range 3 {
+		token, err := cacher.Get(context.Background())
+
+		assert.NoError(err)
+		assert.Equal("foo", token)
+	}
+
+	assert.Equal(1, shared.gets)
+}
+
+func TestLayered_Get_memoryTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := &countingCacher{TokenCacher: NewDefault()}
+	_ = shared.Set(context.Background(), "foo", time.Now().Add(time.Hour))
+
+	cacher := NewLayered(shared).WithMemoryTTL(time.Millisecond * 10)
+
+	_, err := cacher.Get(context.Background())
+	assert.NoError(err)
+
+	time.Sleep(time.Millisecond * 20)
+
+	_, err = cacher.Get(context.Background())
+	assert.NoError(err)
+
+	assert.Equal(2, shared.gets)
+}
+
+func TestLayered_Get_ErrTokenNotExist(t *testing.T) {
+	assert := assert.New(t)
+
+	cacher := NewLayered(NewDefault())
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestLayered_Set(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := NewDefault()
+	cacher := NewLayered(shared)
+
+	expiresAt := time.Now().Add(time.Hour)
+	err := cacher.Set(context.Background(), "foo", expiresAt)
+
+	assert.NoError(err)
+	assert.Equal("foo", cacher.memory.token)
+	assert.Equal(expiresAt, cacher.memory.expiresAt)
+	assert.Equal("foo", shared.token)
+	assert.Equal(expiresAt, shared.expiresAt)
+}
+
+func TestLayered_Invalidate(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := NewDefault()
+	cacher := NewLayered(shared)
+
+	_ = cacher.Set(context.Background(), "foo", time.Now().Add(time.Hour))
+
+	err := cacher.Invalidate(context.Background())
+
+	assert.NoError(err)
+	assert.Empty(cacher.memory.token)
+	assert.Empty(shared.token)
+}
+
+func TestLayered_redis(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	_ = s.Set(RedisDefaultKey, "foo")
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewLayered(NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), ""))
+
+	token, err := cacher.Get(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	assert.WithinDuration(time.Now().Add(time.Minute*1), cacher.memory.expiresAt, time.Second*5)
+
+	// Served from memory.
+	s.Del(RedisDefaultKey)
+
+	token, err = cacher.Get(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("foo", token)
+}
+
+func TestLayered_redis_contextCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	_ = s.Set(RedisDefaultKey, "foo")
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewLayered(NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	token, err := cacher.Get(ctx)
+
+	assert.Empty(token)
+	assert.ErrorIs(err, context.Canceled)
+
+	err = cacher.Set(ctx, "bar", time.Now().Add(time.Minute*1))
+
+	assert.ErrorIs(err, context.Canceled)
+	assert.Empty(cacher.memory.token)
+}
+
+func TestLayered_LockRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewLayered(NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "").WithRefreshLock(time.Minute))
+
+	unlock, err := cacher.LockRefresh(context.Background())
+	assert.NoError(err)
+
+	_, err = cacher.LockRefresh(context.Background())
+	assert.ErrorIs(err, ErrRefreshLocked)
+
+	assert.NoError(unlock(context.Background()))
+}
+
+func TestNewLayered_nil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() {
+		NewLayered(nil)
+	})
+}
+
+func TestLayered_LockRefresh_clearsMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	shared := NewDefault()
+	cacher := NewLayered(shared)
+
+	_ = cacher.Set(context.Background(), "foo", time.Now().Add(time.Hour))
+
+	// Another instance renews the token in the shared tier.
+	expiresAt := time.Now().Add(time.Hour * 2)
+	_ = shared.Set(context.Background(), "bar", expiresAt)
+
+	unlock, err := cacher.LockRefresh(context.Background())
+	assert.NoError(err)
+
+	token, tokenExpiresAt, err := cacher.GetWithExpiry(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("bar", token)
+	assert.Equal(expiresAt, tokenExpiresAt)
+	assert.NoError(unlock(context.Background()))
+}
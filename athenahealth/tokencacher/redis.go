@@ -3,19 +3,36 @@ package tokencacher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 )
 
 const RedisDefaultKey = "athena_token"
 
+// redisRefreshLockSuffix is appended to the token key to form the refresh lock key.
+const redisRefreshLockSuffix = ":refresh_lock"
+
+// redisUnlockScript deletes the refresh lock only if it is still held by the caller, so a lock that
+// expired and was acquired by another instance is not released.
+var redisUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 type Redis struct {
-	client *redis.Client
+	client redis.UniversalClient
 	key    string
+
+	refreshLockTTL time.Duration
 }
 
-func NewRedis(client *redis.Client, key string) *Redis {
+func NewRedis(client redis.UniversalClient, key string) *Redis {
 	if client == nil {
 		panic("client is nil")
 	}
@@ -32,8 +49,55 @@ func NewRedis(client *redis.Client, key string) *Redis {
 	return r
 }
 
+// NewRedisForIdentity returns a Redis token cacher whose key is namespaced by the client ID,
+// practice ID and environment of id (see HTTPClient.Identity), e.g.
+// athena_token:5d41402abc4b2a76:195900:preview, so clients with different credentials can share a
+// Redis. NewRedis with an empty key keeps using RedisDefaultKey.
+func NewRedisForIdentity(client redis.UniversalClient, id identity.Identity) *Redis {
+	return NewRedis(client, RedisKey(id))
+}
+
+// RedisKey returns the key NewRedisForIdentity uses for id.
+func RedisKey(id identity.Identity) string {
+	return fmt.Sprintf("%s:%s:%s", RedisDefaultKey, id.Namespace(), id.Environment())
+}
+
+// WithRefreshLock makes HTTPClients sharing the cache coordinate token refreshes: on a cache miss
+// only the instance holding a Redis lock (SET NX) calls the token provider, while the others wait
+// for it to cache the new token. The lock expires after ttl, so if its holder dies another instance
+// takes over. ttl should be longer than a call to the token provider normally takes.
+func (r *Redis) WithRefreshLock(ttl time.Duration) *Redis {
+	r.refreshLockTTL = ttl
+
+	return r
+}
+
+// LockRefresh implements athenahealth.TokenRefreshLocker. Without WithRefreshLock it always
+// succeeds without taking a lock.
+func (r *Redis) LockRefresh(ctx context.Context) (func(context.Context) error, error) {
+	if r.refreshLockTTL <= 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	key := r.key + redisRefreshLockSuffix
+	value := uuid.NewString()
+
+	ok, err := r.client.SetNX(ctx, key, value, r.refreshLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrRefreshLocked
+	}
+
+	return func(ctx context.Context) error {
+		return redisUnlockScript.Run(ctx, r.client, []string{key}, value).Err()
+	}, nil
+}
+
 func (r *Redis) Get(ctx context.Context) (string, error) {
-	val, err := r.client.Get(context.Background(), r.key).Result()
+	val, err := r.client.Get(ctx, r.key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return "", ErrTokenNotExist
@@ -45,8 +109,47 @@ func (r *Redis) Get(ctx context.Context) (string, error) {
 	return val, nil
 }
 
+// GetWithExpiry returns the cached token and when it expires, from the key's TTL.
+func (r *Redis) GetWithExpiry(ctx context.Context) (string, time.Time, error) {
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+
+	_, err := r.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		get = p.Get(ctx, r.key)
+		ttl = p.PTTL(ctx, r.key)
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", time.Time{}, err
+	}
+
+	val, err := get.Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", time.Time{}, ErrTokenNotExist
+		}
+
+		return "", time.Time{}, err
+	}
+
+	// Keys set by Set always have a TTL; a key without one has an unknown expiry.
+	var expiresAt time.Time
+	if ttl.Val() > 0 {
+		expiresAt = time.Now().Add(ttl.Val())
+	}
+
+	return val, expiresAt, nil
+}
+
 func (r *Redis) Set(ctx context.Context, token string, expiresAt time.Time) error {
-	_, err := r.client.Set(context.Background(), r.key, token, time.Second*time.Duration(expiresAt.Unix()-time.Now().Unix())).Result()
+	_, err := r.client.Set(ctx, r.key, token, time.Second*time.Duration(expiresAt.Unix()-time.Now().Unix())).Result()
+
+	return err
+}
+
+func (r *Redis) Invalidate(ctx context.Context) error {
+	_, err := r.client.Del(ctx, r.key).Result()
 
 	return err
 }
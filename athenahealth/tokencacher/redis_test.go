@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/alicebob/miniredis"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/identity"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/internal/redistest"
 	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 )
@@ -79,3 +81,215 @@ func TestRedis_Set(t *testing.T) {
 	assert.Equal(expectedToken, token)
 	assert.True(time.Now().Add(time.Second * ttl).After(time.Now()))
 }
+
+func TestRedis_Invalidate(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	_ = s.Set(RedisDefaultKey, "foo")
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	err = cacher.Invalidate(context.Background())
+	assert.NoError(err)
+	assert.False(s.Exists(RedisDefaultKey))
+
+	token, err := cacher.Get(context.Background())
+
+	assert.Empty(token)
+	assert.True(errors.Is(err, ErrTokenNotExist))
+}
+
+func TestNewRedisForIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	a := NewRedisForIdentity(client, identity.Identity{ClientID: "a", PracticeID: "195900", Preview: true})
+	b := NewRedisForIdentity(client, identity.Identity{ClientID: "b", PracticeID: "195900", Preview: true})
+
+	err = a.Set(context.Background(), "token-a", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	_, err = b.Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+	assert.False(s.Exists(RedisDefaultKey))
+
+	token, _ := s.Get("athena_token:ca978112ca1bbdca:195900:preview")
+	assert.Equal("token-a", token)
+}
+
+func TestRedis_cluster(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redistest.NewClusterClient(s.Addr()), "")
+
+	err = cacher.Set(context.Background(), "foo", time.Now().Add(time.Minute))
+	assert.NoError(err)
+
+	token, err := cacher.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+
+	err = cacher.Invalidate(context.Background())
+	assert.NoError(err)
+
+	_, err = cacher.Get(context.Background())
+	assert.ErrorIs(err, ErrTokenNotExist)
+}
+
+func TestRedis_LockRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	a := NewRedis(client, "").WithRefreshLock(time.Minute)
+	b := NewRedis(client, "").WithRefreshLock(time.Minute)
+
+	unlock, err := a.LockRefresh(context.Background())
+	assert.NoError(err)
+	assert.True(s.Exists(RedisDefaultKey + redisRefreshLockSuffix))
+	assert.Equal(time.Minute, s.TTL(RedisDefaultKey+redisRefreshLockSuffix))
+
+	_, err = b.LockRefresh(context.Background())
+	assert.ErrorIs(err, ErrRefreshLocked)
+
+	assert.NoError(unlock(context.Background()))
+	assert.False(s.Exists(RedisDefaultKey + redisRefreshLockSuffix))
+
+	unlockB, err := b.LockRefresh(context.Background())
+	assert.NoError(err)
+	assert.NoError(unlockB(context.Background()))
+}
+
+func TestRedis_LockRefresh_expired(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	})
+
+	a := NewRedis(client, "").WithRefreshLock(time.Second)
+	b := NewRedis(client, "").WithRefreshLock(time.Second)
+
+	unlockA, err := a.LockRefresh(context.Background())
+	assert.NoError(err)
+
+	s.FastForward(2 * time.Second)
+
+	_, err = b.LockRefresh(context.Background())
+	assert.NoError(err)
+
+	// a's lock expired, so releasing it must not release b's.
+	assert.NoError(unlockA(context.Background()))
+	assert.True(s.Exists(RedisDefaultKey + redisRefreshLockSuffix))
+}
+
+func TestRedis_LockRefresh_disabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	for range 2 {
+		unlock, err := cacher.LockRefresh(context.Background())
+		assert.NoError(err)
+		assert.NoError(unlock(context.Background()))
+	}
+
+	assert.False(s.Exists(RedisDefaultKey + redisRefreshLockSuffix))
+}
+
+func TestRedis_GetWithExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	_ = s.Set(RedisDefaultKey, "foo")
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	token, expiresAt, err := cacher.GetWithExpiry(context.Background())
+
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	assert.WithinDuration(time.Now().Add(time.Minute*1), expiresAt, time.Second*5)
+}
+
+func TestRedis_Get_contextCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer s.Close()
+
+	_ = s.Set(RedisDefaultKey, "foo")
+	s.SetTTL(RedisDefaultKey, time.Minute*1)
+
+	cacher := NewRedis(redis.NewClient(&redis.Options{
+		Addr: s.Addr(),
+	}), "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	token, err := cacher.Get(ctx)
+
+	assert.Empty(token)
+	assert.ErrorIs(err, context.Canceled)
+
+	err = cacher.Set(ctx, "bar", time.Now().Add(time.Minute*1))
+
+	assert.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,135 @@
+package tokenprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Credentials are the API client ID and secret of an athenahealth app.
+type Credentials struct {
+	ClientID string `json:"clientId"`
+	Secret   string `json:"secret"`
+}
+
+// CredentialsSource provides credentials to Default on every token fetch, so rotated secrets are
+// used on the next token refresh without rebuilding the client.
+type CredentialsSource interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// StaticCredentials is a CredentialsSource for credentials that never change.
+type StaticCredentials struct {
+	credentials Credentials
+}
+
+func NewStaticCredentials(clientID, secret string) *StaticCredentials {
+	return &StaticCredentials{
+		credentials: Credentials{
+			ClientID: clientID,
+			Secret:   secret,
+		},
+	}
+}
+
+func (s *StaticCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	return s.credentials, nil
+}
+
+// FileCredentials is a CredentialsSource that reads credentials from a JSON file, e.g.
+// {"clientId": "...", "secret": "..."}. The file is read again whenever its modification time or
+// size changes, so a rotated secret (e.g. an updated Kubernetes secret volume) is picked up
+// without a restart.
+type FileCredentials struct {
+	path string
+
+	credentials Credentials
+	modTime     time.Time
+	size        int64
+
+	lock sync.Mutex
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	if len(path) == 0 {
+		panic("path required")
+	}
+
+	return &FileCredentials{
+		path: path,
+	}
+}
+
+func (f *FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.credentials, nil
+	}
+
+	contents, err := os.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{}
+
+	err = json.Unmarshal(contents, &credentials)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error unmarshaling credentials: %s", err)
+	}
+
+	if len(credentials.ClientID) == 0 || len(credentials.Secret) == 0 {
+		return Credentials{}, fmt.Errorf("credentials file %s must set clientId and secret", f.path)
+	}
+
+	f.credentials = credentials
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+
+	return credentials, nil
+}
+
+// EnvCredentials is a CredentialsSource that reads credentials from environment variables on every
+// call.
+type EnvCredentials struct {
+	clientIDName string
+	secretName   string
+}
+
+func NewEnvCredentials(clientIDName, secretName string) *EnvCredentials {
+	if len(clientIDName) == 0 || len(secretName) == 0 {
+		panic("names required")
+	}
+
+	return &EnvCredentials{
+		clientIDName: clientIDName,
+		secretName:   secretName,
+	}
+}
+
+func (e *EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	credentials := Credentials{
+		ClientID: os.Getenv(e.clientIDName),
+		Secret:   os.Getenv(e.secretName),
+	}
+
+	if len(credentials.ClientID) == 0 {
+		return Credentials{}, fmt.Errorf("environment variable %s is not set", e.clientIDName)
+	}
+
+	if len(credentials.Secret) == 0 {
+		return Credentials{}, fmt.Errorf("environment variable %s is not set", e.secretName)
+	}
+
+	return credentials, nil
+}
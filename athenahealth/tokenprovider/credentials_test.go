@@ -0,0 +1,83 @@
+package tokenprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	credentials, err := NewStaticCredentials("client", "secret").Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal(Credentials{ClientID: "client", Secret: "secret"}, credentials)
+}
+
+func TestFileCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	assert.NoError(os.WriteFile(path, []byte(`{"clientId":"client","secret":"secret"}`), 0600))
+
+	source := NewFileCredentials(path)
+
+	credentials, err := source.Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal(Credentials{ClientID: "client", Secret: "secret"}, credentials)
+
+	// Rotate the secret.
+	assert.NoError(os.WriteFile(path, []byte(`{"clientId":"client","secret":"rotated"}`), 0600))
+	assert.NoError(os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	credentials, err = source.Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal("rotated", credentials.Secret)
+}
+
+func TestFileCredentials_invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+
+	_, err := NewFileCredentials(filepath.Join(dir, "missing.json")).Credentials(context.Background())
+	assert.Error(err)
+
+	path := filepath.Join(dir, "credentials.json")
+
+	assert.NoError(os.WriteFile(path, []byte(`not json`), 0600))
+	_, err = NewFileCredentials(path).Credentials(context.Background())
+	assert.Error(err)
+
+	assert.NoError(os.WriteFile(path, []byte(`{"clientId":"client"}`), 0600))
+	_, err = NewFileCredentials(path).Credentials(context.Background())
+	assert.ErrorContains(err, "must set clientId and secret")
+}
+
+func TestEnvCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	source := NewEnvCredentials("ATHENA_CLIENT_ID", "ATHENA_SECRET")
+
+	t.Setenv("ATHENA_CLIENT_ID", "client")
+	t.Setenv("ATHENA_SECRET", "")
+
+	_, err := source.Credentials(context.Background())
+	assert.ErrorContains(err, "ATHENA_SECRET")
+
+	t.Setenv("ATHENA_SECRET", "secret")
+
+	credentials, err := source.Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal(Credentials{ClientID: "client", Secret: "secret"}, credentials)
+
+	t.Setenv("ATHENA_SECRET", "rotated")
+
+	credentials, err = source.Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal("rotated", credentials.Secret)
+}
@@ -1,12 +1,11 @@
 package tokenprovider
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -16,75 +15,80 @@ const (
 
 	// ProdAuthURL is the URL used to authenticate in the production environment.
 	ProdAuthURL = "https://api.platform.athenahealth.com/oauth2/v1/token"
+
+	// DefaultScope is the scope requested unless WithScopes is used.
+	DefaultScope = "athena/service/Athenanet.MDP.*"
 )
 
+// Default provides tokens with the client_credentials grant, authenticating with the client ID and
+// secret of a CredentialsSource.
 type Default struct {
 	httpClient *http.Client
 
-	clientID string
-	secret   string
+	credentials CredentialsSource
+	scopes      []string
 
 	authURL string
 }
 
 func NewDefault(httpClient *http.Client, clientID, secret string, preview bool) *Default {
-	d := &Default{
-		httpClient: httpClient,
+	return NewDefaultWithCredentials(httpClient, NewStaticCredentials(clientID, secret), preview)
+}
 
-		clientID: clientID,
-		secret:   secret,
+// NewDefaultWithCredentials returns a Default provider that gets the client ID and secret from
+// credentials on every token fetch.
+func NewDefaultWithCredentials(httpClient *http.Client, credentials CredentialsSource, preview bool) *Default {
+	if credentials == nil {
+		panic("credentials is nil")
 	}
 
-	if preview {
-		d.authURL = PreviewAuthURL
-	} else {
-		d.authURL = ProdAuthURL
-	}
+	return &Default{
+		httpClient: httpClient,
 
-	return d
-}
+		credentials: credentials,
+		scopes:      []string{DefaultScope},
 
-type authResponse struct {
-	AccessToken string      `json:"access_token"`
-	ExpiresIn   json.Number `json:"expires_in"`
+		authURL: authURL(preview),
+	}
 }
 
-func (d *Default) Provide(ctx context.Context) (string, time.Time, error) {
-	vals := url.Values{
-		"grant_type": {"client_credentials"},
-		"scope":      {"athena/service/Athenanet.MDP.*"},
+// WithCredentialsSource replaces the source of the client ID and secret.
+func (d *Default) WithCredentialsSource(credentials CredentialsSource) *Default {
+	if credentials == nil {
+		panic("credentials is nil")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", d.authURL, bytes.NewBufferString(vals.Encode()))
-	if err != nil {
-		return "", time.Now(), err
-	}
+	d.credentials = credentials
 
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(d.clientID, d.secret)
+	return d
+}
 
-	res, err := d.httpClient.Do(req)
-	if err != nil {
-		return "", time.Now(), err
-	}
-	defer func() { _ = res.Body.Close() }()
+// WithPreview sets the environment tokens are requested from.
+func (d *Default) WithPreview(preview bool) *Default {
+	d.authURL = authURL(preview)
 
-	if res.StatusCode != http.StatusOK {
-		return "", time.Now(), fmt.Errorf("%s", res.Status)
-	}
+	return d
+}
 
-	authRes := &authResponse{}
-	err = json.NewDecoder(res.Body).Decode(authRes)
-	if err != nil {
-		return "", time.Now(), err
-	}
+// WithScopes sets the scopes requested, replacing DefaultScope.
+func (d *Default) WithScopes(scopes ...string) *Default {
+	d.scopes = scopes
 
-	expiresIn, err := authRes.ExpiresIn.Int64()
+	return d
+}
+
+func (d *Default) Provide(ctx context.Context) (string, time.Time, error) {
+	credentials, err := d.credentials.Credentials(ctx)
 	if err != nil {
-		return "", time.Now(), err
+		return "", time.Now(), fmt.Errorf("getting credentials: %w", err)
 	}
 
-	expiresAt := time.Now().Add(time.Second * time.Duration(expiresIn))
+	vals := url.Values{
+		"grant_type": {"client_credentials"},
+		"scope":      {strings.Join(d.scopes, " ")},
+	}
 
-	return authRes.AccessToken, expiresAt, nil
+	return requestToken(ctx, d.httpClient, d.authURL, vals, func(req *http.Request) {
+		req.SetBasicAuth(credentials.ClientID, credentials.Secret)
+	})
 }
@@ -3,6 +3,7 @@ package tokenprovider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,8 +22,10 @@ func TestNewDefault(t *testing.T) {
 	p := NewDefault(&http.Client{}, key, secret, preview)
 
 	assert.NotNil(p.httpClient)
-	assert.Equal(key, p.clientID)
-	assert.Equal(secret, p.secret)
+	credentials, err := p.credentials.Credentials(context.Background())
+	assert.NoError(err)
+	assert.Equal(key, credentials.ClientID)
+	assert.Equal(secret, credentials.Secret)
 	assert.Equal(PreviewAuthURL, p.authURL)
 
 	preview = false
@@ -56,3 +59,116 @@ func TestDefault_Provide(t *testing.T) {
 	assert.True(expiresAt.After(time.Now()))
 	assert.NoError(err)
 }
+
+func TestDefault_Provide_scopes(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("system/Patient.read system/Appointment.read", r.FormValue("scope"))
+
+		clientID, secret, ok := r.BasicAuth()
+		assert.True(ok)
+		assert.Equal("client", clientID)
+		assert.Equal("secret", secret)
+
+		_, _ = w.Write([]byte(`{"access_token":"foo","expires_in":"60"}`))
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "client", "secret", false).WithScopes("system/Patient.read", "system/Appointment.read")
+	p.authURL = ts.URL
+
+	token, _, err := p.Provide(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+}
+
+func TestDefault_Provide_oauthError(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed."}`))
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "client", "wrong", false)
+	p.authURL = ts.URL
+
+	_, _, err := p.Provide(context.Background())
+	assert.ErrorIs(err, ErrInvalidClient)
+	assert.False(errors.Is(err, ErrInvalidScope))
+
+	var oauthErr *OAuthError
+	assert.True(errors.As(err, &oauthErr))
+	assert.Equal(http.StatusUnauthorized, oauthErr.StatusCode)
+	assert.Equal("Client authentication failed.", oauthErr.Description)
+	assert.Equal("athenahealth token request failed with status 401: invalid_client: Client authentication failed.", err.Error())
+}
+
+func TestDefault_Provide_nonOAuthError(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`upstream unavailable`))
+	}))
+	defer ts.Close()
+
+	p := NewDefault(ts.Client(), "client", "secret", false)
+	p.authURL = ts.URL
+
+	_, _, err := p.Provide(context.Background())
+
+	var oauthErr *OAuthError
+	assert.True(errors.As(err, &oauthErr))
+	assert.Empty(oauthErr.Code)
+	assert.Equal("upstream unavailable", oauthErr.Body)
+	assert.Nil(oauthErr.Unwrap())
+}
+
+func TestDefault_Provide_credentialsSource(t *testing.T) {
+	assert := assert.New(t)
+
+	var secrets []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, secret, _ := r.BasicAuth()
+		secrets = append(secrets, secret)
+
+		_, _ = w.Write([]byte(`{"access_token":"foo","expires_in":"60"}`))
+	}))
+	defer ts.Close()
+
+	t.Setenv("ATHENA_CLIENT_ID", "client")
+	t.Setenv("ATHENA_SECRET", "secret")
+
+	p := NewDefaultWithCredentials(ts.Client(), NewEnvCredentials("ATHENA_CLIENT_ID", "ATHENA_SECRET"), true)
+	p.authURL = ts.URL
+
+	_, _, err := p.Provide(context.Background())
+	assert.NoError(err)
+
+	t.Setenv("ATHENA_SECRET", "rotated")
+
+	_, _, err = p.Provide(context.Background())
+	assert.NoError(err)
+
+	assert.Equal([]string{"secret", "rotated"}, secrets)
+
+	t.Setenv("ATHENA_SECRET", "")
+
+	_, _, err = p.Provide(context.Background())
+	assert.ErrorContains(err, "getting credentials")
+}
+
+func TestDefault_WithPreview(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewDefault(&http.Client{}, "", "", true).WithScopes("scope")
+
+	p.WithPreview(false)
+	assert.Equal(ProdAuthURL, p.authURL)
+	assert.Equal([]string{"scope"}, p.scopes)
+}
@@ -0,0 +1,232 @@
+package tokenprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // SHA-256 for ES256
+	_ "crypto/sha512" // SHA-384 for RS384 and ES384
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// assertionLifetime is how long a client assertion is valid. SMART backend services allow at
+	// most 5 minutes.
+	assertionLifetime = 5 * time.Minute
+)
+
+// PrivateKeyJWT provides tokens with the client_credentials grant, authenticating with a JWT
+// signed by the client's private key (private_key_jwt, as used by SMART backend services) instead
+// of a secret. The public key must be registered with the app in athenahealth. RSA keys sign with
+// RS384, and P-256 and P-384 ECDSA keys with ES256 and ES384.
+type PrivateKeyJWT struct {
+	httpClient *http.Client
+
+	clientID string
+	key      crypto.Signer
+	keyID    string
+	scopes   []string
+
+	authURL string
+}
+
+// NewPrivateKeyJWT returns a PrivateKeyJWT provider. keyID, if set, is sent as the kid of the
+// JWT so athenahealth can select the matching registered key.
+func NewPrivateKeyJWT(httpClient *http.Client, clientID string, key crypto.Signer, keyID string, preview bool) *PrivateKeyJWT {
+	if key == nil {
+		panic("key is nil")
+	}
+
+	_, _, err := jwtAlgorithm(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return &PrivateKeyJWT{
+		httpClient: httpClient,
+
+		clientID: clientID,
+		key:      key,
+		keyID:    keyID,
+		scopes:   []string{DefaultScope},
+
+		authURL: authURL(preview),
+	}
+}
+
+// WithScopes sets the scopes requested, replacing DefaultScope.
+func (p *PrivateKeyJWT) WithScopes(scopes ...string) *PrivateKeyJWT {
+	p.scopes = scopes
+
+	return p
+}
+
+func (p *PrivateKeyJWT) Provide(ctx context.Context) (string, time.Time, error) {
+	assertion, err := p.assertion(time.Now())
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	vals := url.Values{
+		"grant_type":            {"client_credentials"},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {assertion},
+	}
+
+	return requestToken(ctx, p.httpClient, p.authURL, vals, nil)
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// assertion returns a signed client assertion JWT.
+func (p *PrivateKeyJWT) assertion(now time.Time) (string, error) {
+	alg, hash, err := jwtAlgorithm(p.key)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(&jwtHeader{
+		Algorithm: alg,
+		Type:      "JWT",
+		KeyID:     p.keyID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(&jwtClaims{
+		Issuer:    p.clientID,
+		Subject:   p.clientID,
+		Audience:  p.authURL,
+		ID:        uuid.NewString(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(assertionLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	signature, err := p.key.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return "", fmt.Errorf("signing client assertion: %w", err)
+	}
+
+	if pub, ok := p.key.Public().(*ecdsa.PublicKey); ok {
+		signature, err = jwsECDSASignature(pub, signature)
+		if err != nil {
+			return "", fmt.Errorf("signing client assertion: %w", err)
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// jwsECDSASignature converts an ASN.1 DER ECDSA signature, as returned by every crypto.Signer
+// with an ECDSA key (including KMS and HSM signers), to the fixed-size concatenation of r and s
+// used by JWS.
+func jwsECDSASignature(pub *ecdsa.PublicKey, der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ECDSA signature: %w", err)
+	}
+
+	size := (pub.Curve.Params().BitSize + 7) / 8
+
+	if len(rest) > 0 || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > 8*size || sig.S.BitLen() > 8*size {
+		return nil, errors.New("invalid ECDSA signature")
+	}
+
+	signature := make([]byte, 2*size)
+	sig.R.FillBytes(signature[:size])
+	sig.S.FillBytes(signature[size:])
+
+	return signature, nil
+}
+
+func jwtAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS384", crypto.SHA384, nil
+
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		}
+	}
+
+	return "", 0, errors.New("unsupported key: must be RSA or ECDSA P-256 or P-384")
+}
+
+// ParsePrivateKeyPEM parses a PEM-encoded PKCS #8, PKCS #1 (RSA) or SEC 1 (ECDSA) private key for
+// NewPrivateKeyJWT.
+func ParsePrivateKeyPEM(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported private key format")
+}
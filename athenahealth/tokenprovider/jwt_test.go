@@ -0,0 +1,220 @@
+package tokenprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parseJWT splits a JWT and decodes its header and claims.
+func parseJWT(t *testing.T, token string) (*jwtHeader, *jwtClaims, string, []byte) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("invalid JWT %q", token)
+	}
+
+	header := &jwtHeader{}
+	claims := &jwtClaims{}
+
+	b, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	_ = json.Unmarshal(b, header)
+
+	b, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	_ = json.Unmarshal(b, claims)
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+
+	return header, claims, parts[0] + "." + parts[1], signature
+}
+
+func TestPrivateKeyJWT_Provide(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	var p *PrivateKeyJWT
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("client_credentials", r.FormValue("grant_type"))
+		assert.Equal("system/Patient.read", r.FormValue("scope"))
+		assert.Equal(clientAssertionType, r.FormValue("client_assertion_type"))
+
+		_, _, ok := r.BasicAuth()
+		assert.False(ok)
+
+		header, claims, signingInput, signature := parseJWT(t, r.FormValue("client_assertion"))
+
+		assert.Equal("RS384", header.Algorithm)
+		assert.Equal("JWT", header.Type)
+		assert.Equal("key-1", header.KeyID)
+
+		assert.Equal("client", claims.Issuer)
+		assert.Equal("client", claims.Subject)
+		assert.Equal(p.authURL, claims.Audience)
+		assert.NotEmpty(claims.ID)
+		assert.Equal(int64(assertionLifetime/time.Second), claims.ExpiresAt-claims.IssuedAt)
+
+		digest := sha512.Sum384([]byte(signingInput))
+		assert.NoError(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA384, digest[:], signature))
+
+		_, _ = w.Write([]byte(`{"access_token":"foo","expires_in":"60"}`))
+	}))
+	defer ts.Close()
+
+	p = NewPrivateKeyJWT(ts.Client(), "client", key, "key-1", true).WithScopes("system/Patient.read")
+	p.authURL = ts.URL
+
+	token, expiresAt, err := p.Provide(context.Background())
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	assert.True(expiresAt.After(time.Now()))
+}
+
+func TestPrivateKeyJWT_assertion_ecdsa(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(err)
+
+	p := NewPrivateKeyJWT(&http.Client{}, "client", key, "", false)
+	assert.Equal(ProdAuthURL, p.authURL)
+
+	assertion, err := p.assertion(time.Now())
+	assert.NoError(err)
+
+	header, _, signingInput, signature := parseJWT(t, assertion)
+	assert.Equal("ES384", header.Algorithm)
+	assert.Empty(header.KeyID)
+	assert.Len(signature, 96)
+
+	digest := sha512.Sum384([]byte(signingInput))
+	r := new(big.Int).SetBytes(signature[:48])
+	s := new(big.Int).SetBytes(signature[48:])
+	assert.True(ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+}
+
+// opaqueSigner hides the concrete key type, like a KMS or HSM signer.
+type opaqueSigner struct {
+	key crypto.Signer
+}
+
+func (o *opaqueSigner) Public() crypto.PublicKey {
+	return o.key.Public()
+}
+
+func (o *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return o.key.Sign(rand, digest, opts)
+}
+
+func TestPrivateKeyJWT_assertion_ecdsaSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	p := NewPrivateKeyJWT(&http.Client{}, "client", &opaqueSigner{key: key}, "", false)
+
+	assertion, err := p.assertion(time.Now())
+	assert.NoError(err)
+
+	header, _, signingInput, signature := parseJWT(t, assertion)
+	assert.Equal("ES256", header.Algorithm)
+	assert.Len(signature, 64)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	assert.True(ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+}
+
+func TestJWSECDSASignature_invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	_, err = jwsECDSASignature(&key.PublicKey, []byte("not DER"))
+	assert.Error(err)
+}
+
+func TestPrivateKeyJWT_Provide_oauthError(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_scope"}`))
+	}))
+	defer ts.Close()
+
+	p := NewPrivateKeyJWT(ts.Client(), "client", key, "", true).WithScopes("system/Unknown.read")
+	p.authURL = ts.URL
+
+	_, _, err = p.Provide(context.Background())
+	assert.ErrorIs(err, ErrInvalidScope)
+}
+
+func TestNewPrivateKeyJWT_unsupportedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+
+	assert.Panics(func() {
+		NewPrivateKeyJWT(&http.Client{}, "client", key, "", true)
+	})
+
+	assert.Panics(func() {
+		NewPrivateKeyJWT(&http.Client{}, "client", nil, "", true)
+	})
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	sec1, _ := x509.MarshalECPrivateKey(ecKey)
+
+	for _, block := range []*pem.Block{
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "EC PRIVATE KEY", Bytes: sec1},
+	} {
+		key, err := ParsePrivateKeyPEM(pem.EncodeToMemory(block))
+		assert.NoError(err)
+		assert.NotNil(key)
+	}
+
+	_, err := ParsePrivateKeyPEM([]byte("not a key"))
+	assert.Error(err)
+
+	_, err = ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}))
+	assert.Error(err)
+}
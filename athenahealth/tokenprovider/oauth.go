@@ -0,0 +1,137 @@
+package tokenprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Sentinel errors wrapped by OAuthError, one per OAuth 2.0 error code (RFC 6749 section 5.2).
+var (
+	ErrInvalidRequest       = errors.New("invalid_request")
+	ErrInvalidClient        = errors.New("invalid_client")
+	ErrInvalidGrant         = errors.New("invalid_grant")
+	ErrUnauthorizedClient   = errors.New("unauthorized_client")
+	ErrUnsupportedGrantType = errors.New("unsupported_grant_type")
+	ErrInvalidScope         = errors.New("invalid_scope")
+)
+
+var oauthErrors = map[string]error{
+	ErrInvalidRequest.Error():       ErrInvalidRequest,
+	ErrInvalidClient.Error():        ErrInvalidClient,
+	ErrInvalidGrant.Error():         ErrInvalidGrant,
+	ErrUnauthorizedClient.Error():   ErrUnauthorizedClient,
+	ErrUnsupportedGrantType.Error(): ErrUnsupportedGrantType,
+	ErrInvalidScope.Error():         ErrInvalidScope,
+}
+
+// OAuthError is returned when the token endpoint rejects a request. Use errors.Is with the
+// sentinel errors (e.g. ErrInvalidClient for a wrong secret) to check the error code.
+type OAuthError struct {
+	StatusCode  int
+	Code        string
+	Description string
+	// Body is the response body, for responses that are not OAuth errors.
+	Body string
+}
+
+func (o *OAuthError) Error() string {
+	if len(o.Code) == 0 {
+		return fmt.Sprintf("athenahealth token request failed with status %d: %s", o.StatusCode, o.Body)
+	}
+
+	if len(o.Description) == 0 {
+		return fmt.Sprintf("athenahealth token request failed with status %d: %s", o.StatusCode, o.Code)
+	}
+
+	return fmt.Sprintf("athenahealth token request failed with status %d: %s: %s", o.StatusCode, o.Code, o.Description)
+}
+
+func (o *OAuthError) Unwrap() error {
+	return oauthErrors[o.Code]
+}
+
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func newOAuthError(res *http.Response, body []byte) *OAuthError {
+	oauthErr := &OAuthError{
+		StatusCode: res.StatusCode,
+	}
+
+	errRes := &oauthErrorResponse{}
+	if json.Unmarshal(body, errRes) == nil && len(errRes.Error) > 0 {
+		oauthErr.Code = errRes.Error
+		oauthErr.Description = errRes.ErrorDescription
+	} else {
+		oauthErr.Body = string(body)
+	}
+
+	return oauthErr
+}
+
+type authResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+}
+
+// requestToken posts vals to the token endpoint at authURL. authorize, if not nil, adds client
+// authentication to the request.
+func requestToken(ctx context.Context, httpClient *http.Client, authURL string, vals url.Values, authorize func(*http.Request)) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", authURL, bytes.NewBufferString(vals.Encode()))
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	if authorize != nil {
+		authorize(req)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Now(), err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", time.Now(), newOAuthError(res, body)
+	}
+
+	authRes := &authResponse{}
+	err = json.Unmarshal(body, authRes)
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	expiresIn, err := authRes.ExpiresIn.Int64()
+	if err != nil {
+		return "", time.Now(), err
+	}
+
+	expiresAt := time.Now().Add(time.Second * time.Duration(expiresIn))
+
+	return authRes.AccessToken, expiresAt, nil
+}
+
+func authURL(preview bool) string {
+	if preview {
+		return PreviewAuthURL
+	}
+
+	return ProdAuthURL
+}
@@ -0,0 +1,76 @@
+package athenahealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/stats"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/eleanorhealth/go-athenahealth/athenahealth"
+
+// Span attribute keys. URLs are deliberately not recorded because query strings may contain PHI.
+const (
+	attrHTTPMethod         = "http.request.method"
+	attrHTTPRoute          = "http.route"
+	attrHTTPStatusCode     = "http.response.status_code"
+	attrPracticeID         = "athenahealth.practice_id"
+	attrXRequestID         = "athenahealth.x_request_id"
+	attrAttempts           = "athenahealth.attempts"
+	attrRateLimitWaitMS    = "athenahealth.rate_limit.wait_ms"
+	attrTokenCacheHit      = "athenahealth.token.cache_hit"
+	attrPreviewEnvironment = "athenahealth.preview"
+)
+
+// WithTracerProvider enables OpenTelemetry tracing. Each request is recorded as a client span
+// named after its method and route (e.g. GET /patients/:id:), with child spans for rate limiting
+// and token acquisition.
+func (h *HTTPClient) WithTracerProvider(tp trace.TracerProvider) *HTTPClient {
+	h.tracer = tp.Tracer(tracerName)
+
+	return h
+}
+
+func (h *HTTPClient) startSpan(ctx context.Context, c *call) (context.Context, trace.Span) {
+	route := stats.CleanPath(c.path)
+
+	return h.tracer.Start(ctx, fmt.Sprintf("%s %s", c.method, route),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String(attrHTTPMethod, c.method),
+			attribute.String(attrHTTPRoute, route),
+			attribute.String(attrPracticeID, h.practiceID),
+			attribute.Bool(attrPreviewEnvironment, h.preview),
+		),
+	)
+}
+
+func endSpan(span trace.Span, c *call, res *http.Response, err error) {
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int(attrAttempts, c.attempt),
+		attribute.Int64(attrRateLimitWaitMS, c.rateLimitWait.Milliseconds()),
+	)
+
+	if len(c.xRequestID) > 0 {
+		span.SetAttributes(attribute.String(attrXRequestID, c.xRequestID))
+	}
+
+	if res != nil {
+		span.SetAttributes(attribute.Int(attrHTTPStatusCode, res.StatusCode))
+	}
+
+	if err != nil {
+		recordSpanError(span, err)
+	}
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,131 @@
+package athenahealth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/ratelimiter"
+	"github.com/eleanorhealth/go-athenahealth/athenahealth/tokencacher"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestHTTPClient_WithTracerProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	var xRequestID string
+	calls := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		xRequestID = r.Header.Get(XRequestIDHeaderKey)
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	rateLimited := false
+	rateLimiter := &testRateLimiter{}
+	rateLimiter.AllowedFunc = func(preview bool) (time.Duration, error) {
+		if rateLimited {
+			return 0, nil
+		}
+
+		rateLimited = true
+
+		return 20 * time.Millisecond, ratelimiter.ErrRateExceeded
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	athenaClient.WithTracerProvider(tp).
+		WithRateLimiter(rateLimiter).
+		WithTokenCacher(tokencacher.NewDefault()).
+		WithRetryPolicy(testRetryPolicy())
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients/123?showinsurance=true", nil, nil, nil)
+	assert.NoError(err)
+
+	spans := recorder.Ended()
+
+	var requestSpan sdktrace.ReadOnlySpan
+	names := make(map[string]int)
+
+	for _, span := range spans {
+		names[span.Name()]++
+
+		if span.Name() == "GET /patients/:id:" {
+			requestSpan = span
+		}
+	}
+
+	assert.Equal(2, names["athenahealth.rate_limit"])
+	assert.Equal(2, names["athenahealth.token"])
+
+	if !assert.NotNil(requestSpan) {
+		return
+	}
+
+	assert.Equal(trace.SpanKindClient, requestSpan.SpanKind())
+
+	attrs := spanAttributes(requestSpan)
+	assert.Equal(http.MethodGet, attrs[attrHTTPMethod].AsString())
+	assert.Equal("/patients/:id:", attrs[attrHTTPRoute].AsString())
+	assert.Equal(testPracticeID, attrs[attrPracticeID].AsString())
+	assert.Equal(int64(http.StatusOK), attrs[attrHTTPStatusCode].AsInt64())
+	assert.Equal(xRequestID, attrs[attrXRequestID].AsString())
+	assert.Equal(int64(2), attrs[attrAttempts].AsInt64())
+	assert.GreaterOrEqual(attrs[attrRateLimitWaitMS].AsInt64(), int64(20))
+
+	for _, span := range spans {
+		if span.Name() != requestSpan.Name() {
+			assert.Equal(requestSpan.SpanContext().SpanID(), span.Parent().SpanID())
+		}
+	}
+}
+
+func TestHTTPClient_WithTracerProvider_error(t *testing.T) {
+	assert := assert.New(t)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	athenaClient, ts := testClient(h)
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	athenaClient.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, err := athenaClient.request(context.Background(), http.MethodGet, "/patients/123", nil, nil, nil)
+	assert.Error(err)
+
+	spans := recorder.Ended()
+	requestSpan := spans[len(spans)-1]
+
+	assert.Equal("GET /patients/:id:", requestSpan.Name())
+	assert.Equal(codes.Error, requestSpan.Status().Code)
+	assert.Equal(int64(http.StatusNotFound), spanAttributes(requestSpan)[attrHTTPStatusCode].AsInt64())
+}